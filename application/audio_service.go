@@ -44,6 +44,11 @@ const (
 	WarningGap = 100 * time.Millisecond
 	WarningCycles = 5
 	
+	ChimeSoundFreq = 1047
+	ChimeSoundDuration = 300 * time.Millisecond
+	ChimeVolumeScale = 0.5
+	AlarmRepeats = 2
+	
 	PauseBeepFreq = 400
 	ResumeBeepFreq = 600
 	BeepDuration = 100 * time.Millisecond
@@ -54,6 +59,7 @@ type AudioService struct {
 	readyChannel chan struct{}
 	isReady      bool
 	volume       float64
+	enabled      bool
 	initError    error
 }
 
@@ -62,6 +68,7 @@ func NewAudioService() *AudioService {
 		readyChannel: make(chan struct{}),
 		isReady:      false,
 		volume:       DefaultVolume,
+		enabled:      true,
 	}
 	
 	go service.initialize()
@@ -103,12 +110,25 @@ func (a *AudioService) WaitForReady(timeout time.Duration) bool {
 }
 
 func (a *AudioService) PlayBeep(frequency float64, duration time.Duration) error {
+	return a.playBeep(frequency, duration, a.volume, a.enabled)
+}
+
+// PlayBeepAt plays a tone at volume, even with sound off, without touching
+// the configured volume.
+func (a *AudioService) PlayBeepAt(frequency float64, duration time.Duration, volume float64) error {
+	return a.playBeep(frequency, duration, clampVolume(volume), true)
+}
+
+func (a *AudioService) playBeep(frequency float64, duration time.Duration, volume float64, enabled bool) error {
 	if a.initError != nil {
 		return a.initError
 	}
 	if !a.isReady {
 		return domain.ErrAudioNotReady
 	}
+	if !enabled {
+		return nil
+	}
 	
 	samples := int(float64(SampleRate) * duration.Seconds())
 	
@@ -116,7 +136,7 @@ func (a *AudioService) PlayBeep(frequency float64, duration time.Duration) error
 	
 	for i := 0; i < samples; i++ {
 		t := float64(i) / float64(SampleRate)
-		sample := int16(MaxAmplitude * a.volume * BaseAmplitude * 
+		sample := int16(MaxAmplitude * volume * BaseAmplitude * 
 			(math.Sin(2*math.Pi*frequency*t) + 
 			 math.Sin(2*math.Pi*frequency*2*t)*Harmonic2Amplitude + 
 			 math.Sin(2*math.Pi*frequency*3*t)*Harmonic3Amplitude))
@@ -137,39 +157,60 @@ func (a *AudioService) PlayBeep(frequency float64, duration time.Duration) error
 	return nil
 }
 
-func (a *AudioService) PlayStartSound() error {
-	return a.PlayBeep(StartSoundFreq, StartSoundDuration)
+// sounds plays the sound patterns at volume, one tone at a time with beep.
+type sounds struct {
+	beep   func(frequency float64, duration time.Duration, volume float64) error
+	volume float64
 }
 
-func (a *AudioService) PlayEndSound() error {
+// sounds are the patterns at the configured volume, silent while sound is off.
+func (a *AudioService) sounds() sounds {
+	enabled := a.enabled
+	return sounds{
+		beep: func(frequency float64, duration time.Duration, volume float64) error {
+			return a.playBeep(frequency, duration, volume, enabled)
+		},
+		volume: a.volume,
+	}
+}
+
+func (s sounds) play(frequency float64, duration time.Duration) error {
+	return s.beep(frequency, duration, s.volume)
+}
+
+func (s sounds) start() error {
+	return s.play(StartSoundFreq, StartSoundDuration)
+}
+
+func (s sounds) end() error {
 	// セッション終了を強力に通知
-	if err := a.PlayBeep(EndSound1Freq, EndSound1Duration); err != nil {
+	if err := s.play(EndSound1Freq, EndSound1Duration); err != nil {
 		return fmt.Errorf("%w: first beep failed: %v", domain.ErrAudioPlayback, err)
 	}
 	time.Sleep(EndSoundGap)
-	if err := a.PlayBeep(EndSound2Freq, EndSound2Duration); err != nil {
+	if err := s.play(EndSound2Freq, EndSound2Duration); err != nil {
 		return fmt.Errorf("%w: second beep failed: %v", domain.ErrAudioPlayback, err)
 	}
 	time.Sleep(EndSoundGap)
-	if err := a.PlayBeep(EndSound3Freq, EndSound3Duration); err != nil {
+	if err := s.play(EndSound3Freq, EndSound3Duration); err != nil {
 		return fmt.Errorf("%w: third beep failed: %v", domain.ErrAudioPlayback, err)
 	}
 	time.Sleep(EndSoundLongGap)
 	// 追加の強調音
-	if err := a.PlayBeep(EndSound4Freq, EndSound4Duration); err != nil {
+	if err := s.play(EndSound4Freq, EndSound4Duration); err != nil {
 		return fmt.Errorf("%w: fourth beep failed: %v", domain.ErrAudioPlayback, err)
 	}
 	return nil
 }
 
-func (a *AudioService) PlayWarningSound() error {
+func (s sounds) warning() error {
 	// より強力で持続的な警告音を再生
 	for i := 0; i < WarningCycles; i++ {
-		if err := a.PlayBeep(WarningHighFreq, WarningDuration); err != nil {
+		if err := s.play(WarningHighFreq, WarningDuration); err != nil {
 			return fmt.Errorf("%w: warning high beep cycle %d failed: %v", domain.ErrAudioPlayback, i, err)
 		}
 		time.Sleep(WarningGap)
-		if err := a.PlayBeep(WarningLowFreq, WarningDuration); err != nil {
+		if err := s.play(WarningLowFreq, WarningDuration); err != nil {
 			return fmt.Errorf("%w: warning low beep cycle %d failed: %v", domain.ErrAudioPlayback, i, err)
 		}
 		time.Sleep(WarningGap)
@@ -177,14 +218,103 @@ func (a *AudioService) PlayWarningSound() error {
 	return nil
 }
 
+func (s sounds) chime() error {
+	return s.beep(ChimeSoundFreq, ChimeSoundDuration, s.volume*ChimeVolumeScale)
+}
+
+func (s sounds) alarm() error {
+	loud := sounds{beep: s.beep, volume: 1}
+	for i := 0; i < AlarmRepeats; i++ {
+		if err := loud.warning(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *AudioService) PlayStartSound() error {
+	return a.sounds().start()
+}
+
+func (a *AudioService) PlayEndSound() error {
+	return a.sounds().end()
+}
+
+func (a *AudioService) PlayWarningSound() error {
+	return a.sounds().warning()
+}
+
+// PlayChimeSound plays a single soft tone for the gentlest idle warning.
+func (a *AudioService) PlayChimeSound() error {
+	return a.sounds().chime()
+}
+
+// PlayAlarmSound plays the warning pattern repeatedly at full volume.
+func (a *AudioService) PlayAlarmSound() error {
+	return a.sounds().alarm()
+}
+
 func (a *AudioService) SetVolume(volume float64) {
+	a.volume = clampVolume(volume)
+}
+
+func clampVolume(volume float64) float64 {
 	if volume < 0 {
-		volume = 0
+		return 0
 	}
 	if volume > 1 {
-		volume = 1
+		return 1
 	}
-	a.volume = volume
+	return volume
+}
+
+// SetEnabled turns all sounds on or off.
+func (a *AudioService) SetEnabled(enabled bool) {
+	a.enabled = enabled
+}
+
+// Preview returns a player that plays every sound at volume, even with
+// sound off, so that a volume can be tried before it is saved. The volume
+// and switch of a are left alone.
+func (a *AudioService) Preview(volume float64) domain.AudioPlayer {
+	return &previewPlayer{audio: a, volume: clampVolume(volume)}
+}
+
+type previewPlayer struct {
+	audio  *AudioService
+	volume float64
+}
+
+func (p *previewPlayer) sounds() sounds {
+	return sounds{beep: p.audio.PlayBeepAt, volume: p.volume}
+}
+
+func (p *previewPlayer) PlayStartSound() error {
+	return p.sounds().start()
+}
+
+func (p *previewPlayer) PlayEndSound() error {
+	return p.sounds().end()
+}
+
+func (p *previewPlayer) PlayWarningSound() error {
+	return p.sounds().warning()
+}
+
+func (p *previewPlayer) PlayChimeSound() error {
+	return p.sounds().chime()
+}
+
+func (p *previewPlayer) PlayAlarmSound() error {
+	return p.sounds().alarm()
+}
+
+func (p *previewPlayer) IsReady() bool {
+	return p.audio.IsReady()
+}
+
+func (p *previewPlayer) PlayBeep(frequency float64, duration time.Duration) error {
+	return p.audio.PlayBeepAt(frequency, duration, p.volume)
 }
 
 func (a *AudioService) IsReady() bool {
@@ -0,0 +1,68 @@
+package application
+
+import "reflect"
+
+// configField is a setting named as in config.json.
+type configField struct {
+	name  string
+	value func(*Config) interface{}
+}
+
+// localOnlyFields run programs, write files or open the API itself, so only
+// whoever can edit the config file may change them.
+var localOnlyFields = []configField{
+	{"notifications.sinks", func(c *Config) interface{} { return c.Notifications.Sinks }},
+	{"notifications.command", func(c *Config) interface{} { return c.Notifications.Command }},
+	{"notifications.log_file", func(c *Config) interface{} { return c.Notifications.LogFile }},
+	{"hooks", func(c *Config) interface{} { return c.Hooks }},
+	{"webhooks", func(c *Config) interface{} { return c.Webhooks }},
+	{"http", func(c *Config) interface{} { return c.HTTP }},
+	{"font", func(c *Config) interface{} { return c.Font }},
+}
+
+// restartFields take effect only when karedoro starts again; ApplyConfig
+// saves them but the running instance keeps the old ones.
+var restartFields = []configField{
+	{"locale", func(c *Config) interface{} { return c.Locale }},
+	{"font", func(c *Config) interface{} { return c.Font }},
+	{"keys", func(c *Config) interface{} { return c.Keys }},
+	{"http", func(c *Config) interface{} { return c.HTTP }},
+	{"dbus", func(c *Config) interface{} { return c.DBus }},
+	{"hooks", func(c *Config) interface{} { return c.Hooks }},
+	{"webhooks", func(c *Config) interface{} { return c.Webhooks }},
+}
+
+// LocalOnlyChanges lists the settings changed from previous to config that
+// may only be changed in the config file, not through the API.
+func LocalOnlyChanges(previous, config *Config) []string {
+	return changedFields(localOnlyFields, previous, config)
+}
+
+// RestartChanges lists the settings changed from previous to config that
+// take effect only after a restart.
+func RestartChanges(previous, config *Config) []string {
+	return changedFields(restartFields, previous, config)
+}
+
+func changedFields(fields []configField, previous, config *Config) []string {
+	var changed []string
+	for _, field := range fields {
+		if !sameValue(field.value(previous), field.value(config)) {
+			changed = append(changed, field.name)
+		}
+	}
+	return changed
+}
+
+// sameValue is reflect.DeepEqual, except that a nil and an empty slice or
+// map are the same setting.
+func sameValue(a, b interface{}) bool {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	switch va.Kind() {
+	case reflect.Slice, reflect.Map:
+		if va.Len() == 0 && vb.Len() == 0 {
+			return true
+		}
+	}
+	return reflect.DeepEqual(a, b)
+}
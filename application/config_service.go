@@ -2,9 +2,13 @@ package application
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 	"time"
+
+	"karedoro/domain"
+	"karedoro/i18n"
 )
 
 type Config struct {
@@ -13,6 +17,75 @@ type Config struct {
 	WarningInterval time.Duration `json:"warning_interval"`
 	SoundEnabled    bool          `json:"sound_enabled"`
 	Volume          float64       `json:"volume"`
+	
+	// Locale selects the message language; empty follows the environment.
+	Locale string `json:"locale"`
+	
+	// Font is a TrueType or OpenType file for the window's text, relative to
+	// the config directory; empty uses the built-in font.
+	Font string `json:"font"`
+	
+	// Theme chooses the window colours.
+	Theme ThemeConfig `json:"theme"`
+	
+	// Keys binds the window's actions to keys; actions left out keep their
+	// default keys.
+	Keys Keymap `json:"keys"`
+	
+	// WarningLadder escalates idle warnings; WarningInterval is the repeat
+	// interval once the last step has fired.
+	WarningLadder []domain.WarningStep `json:"warning_ladder"`
+	
+	// Pause limits a session's pauses; strict mode disables them entirely.
+	Pause domain.PausePolicy `json:"pause"`
+	
+	// Skip limits how many due breaks may be skipped per day.
+	Skip domain.SkipPolicy `json:"skip"`
+	
+	// AutoStart starts the next session after a countdown on the overlay.
+	AutoStart domain.AutoStartPolicy `json:"auto_start"`
+	
+	// Overtime allows continuing a finished work session up to a cap.
+	Overtime domain.OvertimePolicy `json:"overtime"`
+	
+	// Flowtime decides the break earned by an open-ended flow session.
+	Flowtime domain.FlowtimePolicy `json:"flowtime"`
+	
+	// Extend limits how often a running session may be extended.
+	Extend domain.ExtendPolicy `json:"extend"`
+	
+	// HTTP configures the optional local REST API.
+	HTTP HTTPConfig `json:"http"`
+	
+	// DBus exports the timer on the session bus.
+	DBus DBusConfig `json:"dbus"`
+	
+	// Notifications chooses the sinks that show notifications.
+	Notifications NotificationsConfig `json:"notifications"`
+	
+	// Hooks configures the scripts run from the hooks directory.
+	Hooks HooksConfig `json:"hooks"`
+	
+	// Webhooks are HTTP endpoints notified of session events.
+	Webhooks []WebhookConfig `json:"webhooks"`
+}
+
+// DBusConfig controls the org.karedoro.Timer object on the session bus,
+// which is exported by default where a session bus is available.
+type DBusConfig struct {
+	Enabled bool `json:"enabled"`
+}
+
+// HTTPConfig controls the local REST API and web UI. It is off by default;
+// when Token is empty a random one is generated at startup. AllowLAN permits
+// a non-loopback Addr so that the web UI can be opened from other devices.
+// Metrics adds a Prometheus /metrics endpoint, which also needs the token.
+type HTTPConfig struct {
+	Enabled  bool   `json:"enabled"`
+	Addr     string `json:"addr"`
+	Token    string `json:"token"`
+	AllowLAN bool   `json:"allow_lan"`
+	Metrics  bool   `json:"metrics"`
 }
 
 func DefaultConfig() *Config {
@@ -22,9 +95,103 @@ func DefaultConfig() *Config {
 		WarningInterval: 5 * time.Minute,
 		SoundEnabled:    true,
 		Volume:          0.7,
+		WarningLadder:   domain.DefaultWarningLadder(),
+		Pause:           domain.DefaultPausePolicy(),
+		Skip:            domain.DefaultSkipPolicy(),
+		AutoStart:       domain.DefaultAutoStartPolicy(),
+		Overtime:        domain.DefaultOvertimePolicy(),
+		Flowtime:        domain.DefaultFlowtimePolicy(),
+		Extend:          domain.DefaultExtendPolicy(),
+		HTTP: HTTPConfig{
+			Addr: "127.0.0.1:7323",
+		},
+		DBus: DBusConfig{
+			Enabled: true,
+		},
+		Notifications: DefaultNotificationsConfig(),
+		Theme:         DefaultThemeConfig(),
+		Keys:          DefaultKeymap(),
+		Hooks: HooksConfig{
+			Timeout: DefaultHookTimeout,
+		},
 	}
 }
 
+// Validate reports whether the settings in c can be applied.
+func (c *Config) Validate() error {
+	if err := c.validateSession(); err != nil {
+		return err
+	}
+	if c.Volume < 0 || c.Volume > 1 {
+		return fmt.Errorf("volume %v is out of range (0 to 1)", c.Volume)
+	}
+	if _, err := i18n.Load(c.Locale, ""); err != nil {
+		return err
+	}
+	if err := c.Notifications.Validate(); err != nil {
+		return err
+	}
+	if err := c.Theme.Validate(); err != nil {
+		return err
+	}
+	if err := c.Keys.Validate(); err != nil {
+		return err
+	}
+	for _, webhook := range c.Webhooks {
+		if err := webhook.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateSession checks the settings that SessionService.Configure applies,
+// with the same domain validators the session uses.
+func (c *Config) validateSession() error {
+	if err := domain.ValidateDurations(c.WorkDuration, c.BreakDuration); err != nil {
+		return domain.NewSessionError("set durations", err)
+	}
+	if err := domain.ValidateWarningLadder(c.WarningLadder); err != nil {
+		return domain.NewSessionError("set warning ladder", err)
+	}
+	if err := domain.ValidateWarningRepeat(c.WarningInterval); err != nil {
+		return domain.NewSessionError("set warning ladder", err)
+	}
+	if err := c.Pause.Validate(); err != nil {
+		return domain.NewSessionError("set pause policy", err)
+	}
+	if err := c.Skip.Validate(); err != nil {
+		return domain.NewSessionError("set skip policy", err)
+	}
+	if err := c.AutoStart.Validate(); err != nil {
+		return domain.NewSessionError("set auto-start policy", err)
+	}
+	if err := c.Overtime.Validate(); err != nil {
+		return domain.NewSessionError("set overtime policy", err)
+	}
+	if err := c.Extend.Validate(); err != nil {
+		return domain.NewSessionError("set extend policy", err)
+	}
+	if err := c.Flowtime.Validate(); err != nil {
+		return domain.NewSessionError("set flowtime policy", err)
+	}
+	return nil
+}
+
+// Clone returns a deep copy of c. Decoding JSON into the copy then leaves
+// the slices and maps of c alone.
+func (c *Config) Clone() *Config {
+	data, err := json.Marshal(c)
+	if err != nil {
+		panic(err)
+	}
+	clone := &Config{}
+	if err := json.Unmarshal(data, clone); err != nil {
+		panic(err)
+	}
+	return clone
+}
+
 type ConfigService struct {
 	config     *Config
 	configPath string
@@ -56,9 +223,14 @@ func (c *ConfigService) Load() error {
 	return json.Unmarshal(data, c.config)
 }
 
+// Save writes the config readable only by the user, since it holds the API
+// token and webhook secrets. Files written by older versions are tightened.
 func (c *ConfigService) Save() error {
 	dir := filepath.Dir(c.configPath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	if err := os.Chmod(dir, 0700); err != nil {
 		return err
 	}
 	
@@ -67,7 +239,15 @@ func (c *ConfigService) Save() error {
 		return err
 	}
 	
-	return os.WriteFile(c.configPath, data, 0644)
+	if err := os.Chmod(c.configPath, 0600); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return os.WriteFile(c.configPath, data, 0600)
+}
+
+// Dir returns the directory holding the config file.
+func (c *ConfigService) Dir() string {
+	return filepath.Dir(c.configPath)
 }
 
 func (c *ConfigService) GetConfig() *Config {
@@ -3,6 +3,7 @@ package application
 import (
 	"os"
 	"path/filepath"
+	"runtime"
 	"testing"
 	"time"
 )
@@ -140,6 +141,41 @@ func TestConfigService_ConfigFileCreation(t *testing.T) {
 	}
 }
 
+func TestConfigService_SaveIsPrivate(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file modes are not enforced on Windows")
+	}
+	tempDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", tempDir)
+	defer os.Setenv("HOME", originalHome)
+	
+	// 以前の版が作った、誰でも読めるファイル
+	dir := filepath.Join(tempDir, ".karedoro")
+	if err := os.Mkdir(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	configPath := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(configPath, []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	
+	service := NewConfigService()
+	if err := service.Save(); err != nil {
+		t.Fatalf("Save should not return error, got %v", err)
+	}
+	
+	for path, want := range map[string]os.FileMode{dir: 0700, configPath: 0600} {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if perm := info.Mode().Perm(); perm != want {
+			t.Errorf("Expected %s to have mode %o, got %o", path, want, perm)
+		}
+	}
+}
+
 func TestConfigService_SaveError(t *testing.T) {
 	// Create temporary directory for test
 	tempDir, err := os.MkdirTemp("", "karedoro_test")
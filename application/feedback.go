@@ -0,0 +1,131 @@
+package application
+
+import (
+	"log"
+	"time"
+
+	"karedoro/domain"
+)
+
+// FeedbackHandler plays sounds and shows notifications for session events.
+// It has no UI of its own, so it serves both the windowed app and the daemon.
+type FeedbackHandler struct {
+	audioService        domain.AudioPlayer
+	notificationService domain.NotificationSender
+	
+	// onError is told about failed sounds and notifications; nil ignores them.
+	onError func(kind string, err error)
+}
+
+func NewFeedbackHandler(audioService domain.AudioPlayer, notificationService domain.NotificationSender) *FeedbackHandler {
+	return &FeedbackHandler{
+		audioService:        audioService,
+		notificationService: notificationService,
+	}
+}
+
+// SetErrorHandler reports failed sounds and notifications, with kind
+// FailureAudio or FailureNotification, to onError.
+func (fh *FeedbackHandler) SetErrorHandler(onError func(kind string, err error)) {
+	fh.onError = onError
+}
+
+func (fh *FeedbackHandler) sound(err error) {
+	if err != nil && fh.onError != nil {
+		fh.onError(FailureAudio, err)
+	}
+}
+
+// notify logs a notification that no sink could show, since unlike a
+// missing sound it leaves the user uninformed.
+func (fh *FeedbackHandler) notify(err error) {
+	if err == nil {
+		return
+	}
+	log.Printf("Warning: notification failed: %v", err)
+	if fh.onError != nil {
+		fh.onError(FailureNotification, err)
+	}
+}
+
+// Attach subscribes the handler to the session's events.
+func (fh *FeedbackHandler) Attach(sessionService *SessionService) {
+	sessionService.AddEventCallback(domain.EventWorkSessionStart, func() {
+		fh.sound(fh.audioService.PlayStartSound())
+		fh.notify(fh.notificationService.ShowWorkSessionStart())
+	})
+	
+	sessionService.AddEventCallback(domain.EventBreakSessionStart, func() {
+		fh.sound(fh.audioService.PlayStartSound())
+		fh.notify(fh.notificationService.ShowBreakSessionStart())
+	})
+	
+	sessionService.AddEventCallback(domain.EventWorkSessionEnd, func() {
+		fh.sound(fh.audioService.PlayEndSound())
+		fh.notify(fh.notificationService.ShowWorkSessionEnd())
+	})
+	
+	sessionService.AddEventCallback(domain.EventFlowSessionStart, func() {
+		fh.sound(fh.audioService.PlayStartSound())
+		fh.notify(fh.notificationService.ShowFlowSessionStart())
+	})
+	
+	sessionService.AddEventCallback(domain.EventFlowSessionEnd, func() {
+		fh.sound(fh.audioService.PlayEndSound())
+		fh.notify(fh.notificationService.ShowFlowSessionEnd())
+	})
+	
+	sessionService.AddEventCallback(domain.EventBreakSessionEnd, func() {
+		fh.sound(fh.audioService.PlayEndSound())
+		fh.notify(fh.notificationService.ShowBreakSessionEnd())
+	})
+	
+	// 待機警告は段階的にエスカレートする
+	sessionService.AddEventCallback(domain.EventWarningGentle, func() {
+		fh.sound(fh.audioService.PlayChimeSound())
+	})
+	
+	sessionService.AddEventCallback(domain.EventWarningNotice, func() {
+		fh.sound(fh.audioService.PlayChimeSound())
+		fh.notify(fh.notificationService.ShowWarning())
+	})
+	
+	sessionService.AddEventCallback(domain.EventWarningUrgent, func() {
+		fh.sound(fh.audioService.PlayAlarmSound())
+		fh.notify(fh.notificationService.ShowWarning())
+	})
+	
+	sessionService.AddEventCallback(domain.EventWarningCritical, func() {
+		fh.sound(fh.audioService.PlayAlarmSound())
+		fh.notify(fh.notificationService.ShowWarning())
+	})
+	
+	sessionService.AddEventCallback(domain.EventSessionPause, func() {
+		fh.sound(fh.audioService.PlayBeep(400, 100*time.Millisecond))
+		fh.notify(fh.notificationService.ShowSessionPaused())
+	})
+	
+	sessionService.AddEventCallback(domain.EventSessionResume, func() {
+		fh.sound(fh.audioService.PlayBeep(600, 100*time.Millisecond))
+		fh.notify(fh.notificationService.ShowSessionResumed())
+	})
+	
+	sessionService.AddEventCallback(domain.EventOvertimeCapped, func() {
+		fh.sound(fh.audioService.PlayEndSound())
+		fh.notify(fh.notificationService.ShowWorkSessionEnd())
+	})
+	
+	sessionService.AddEventCallback(domain.EventSessionExtended, func() {
+		fh.sound(fh.audioService.PlayBeep(600, 100*time.Millisecond))
+	})
+	
+	sessionService.AddEventCallback(domain.EventPauseReminder, func() {
+		fh.sound(fh.audioService.PlayChimeSound())
+		fh.notify(fh.notificationService.ShowPauseReminder())
+	})
+	
+	sessionService.AddEventCallback(domain.EventSessionAbandon, func() {
+		fh.sound(fh.audioService.PlayBeep(400, 100*time.Millisecond))
+		fh.notify(fh.notificationService.ShowSessionAbandoned())
+	})
+}
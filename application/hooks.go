@@ -0,0 +1,156 @@
+package application
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"time"
+)
+
+// DefaultHookTimeout is how long a hook may run before it is killed.
+const DefaultHookTimeout = 10 * time.Second
+
+// hookQueueSize bounds the hooks waiting to run; events beyond it are dropped
+// rather than blocking the session.
+const hookQueueSize = 32
+
+// hookWindowsExts are the extensions tried for hooks on Windows, where
+// files have no executable bit.
+var hookWindowsExts = []string{".exe", ".bat", ".cmd"}
+
+// HooksConfig controls the user's hook scripts.
+type HooksConfig struct {
+	Timeout time.Duration `json:"timeout"`
+}
+
+// HookRunner runs git-hooks-style executables named after session events,
+// e.g. hooks/work_session_start. Hooks run one at a time in event order on
+// their own goroutine, so a slow hook never holds up the session.
+type HookRunner struct {
+	dir     string
+	timeout time.Duration
+	jobs    chan hookJob
+}
+
+type hookJob struct {
+	event string
+	path  string
+	env   []string
+}
+
+// HooksDir is where the user's hooks live: karedoro/hooks in the user's
+// config directory, e.g. ~/.config/karedoro/hooks.
+func HooksDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "karedoro", "hooks"), nil
+}
+
+func NewHookRunner(dir string, timeout time.Duration) *HookRunner {
+	if timeout <= 0 {
+		timeout = DefaultHookTimeout
+	}
+	runner := &HookRunner{
+		dir:     dir,
+		timeout: timeout,
+		jobs:    make(chan hookJob, hookQueueSize),
+	}
+	go runner.work()
+	return runner
+}
+
+// Attach runs the matching hook, if there is one, for every session event.
+func (h *HookRunner) Attach(sessionService *SessionService) {
+	sessionService.AddEventListener(func(event string) {
+		path := h.find(event)
+		if path == "" {
+			return
+		}
+		
+		job := hookJob{event: event, path: path, env: HookEnv(event, sessionService.Status())}
+		select {
+		case h.jobs <- job:
+		default:
+			log.Printf("hook %s: dropped, too many hooks pending", event)
+		}
+	})
+}
+
+// find returns the executable hook for event, or "" if there is none.
+func (h *HookRunner) find(event string) string {
+	path := filepath.Join(h.dir, event)
+	if runtime.GOOS == "windows" {
+		for _, ext := range hookWindowsExts {
+			if info, err := os.Stat(path + ext); err == nil && info.Mode().IsRegular() {
+				return path + ext
+			}
+		}
+		return ""
+	}
+	
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() || info.Mode().Perm()&0111 == 0 {
+		return ""
+	}
+	return path
+}
+
+func (h *HookRunner) work() {
+	for job := range h.jobs {
+		h.run(job)
+	}
+}
+
+func (h *HookRunner) run(job hookJob) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.timeout)
+	defer cancel()
+	
+	cmd := exec.CommandContext(ctx, job.path)
+	cmd.Dir = h.dir
+	cmd.Env = append(os.Environ(), job.env...)
+	// バックグラウンドに残った子プロセスが出力を握ったままでも待ち続けない
+	cmd.WaitDelay = time.Second
+	
+	output, err := cmd.CombinedOutput()
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		log.Printf("hook %s: %s", job.event, scanner.Text())
+	}
+	
+	switch {
+	case ctx.Err() == context.DeadlineExceeded:
+		log.Printf("hook %s: killed after %v", job.event, h.timeout)
+	case err != nil:
+		log.Printf("hook %s: %v", job.event, err)
+	}
+}
+
+// HookEnv returns the environment variables describing an event.
+func HookEnv(event string, status Status) []string {
+	return []string{
+		"KAREDORO_EVENT=" + event,
+		"KAREDORO_STATE=" + status.State,
+		"KAREDORO_SESSION_TYPE=" + status.SessionType,
+		"KAREDORO_REMAINING=" + strconv.Itoa(status.Remaining),
+		"KAREDORO_ELAPSED=" + strconv.Itoa(status.Elapsed),
+		"KAREDORO_PAUSED=" + boolEnv(status.Paused),
+		"KAREDORO_BREAK_DUE=" + boolEnv(status.BreakDue),
+		fmt.Sprintf("KAREDORO_PROGRESS=%.2f", status.Progress),
+	}
+}
+
+func boolEnv(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
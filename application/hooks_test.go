@@ -0,0 +1,104 @@
+package application
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"karedoro/domain"
+)
+
+func writeHook(t *testing.T, dir, name, script string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+script+"\n"), 0755); err != nil {
+		t.Fatalf("Failed to write hook: %v", err)
+	}
+}
+
+func waitForFile(t *testing.T, path string) string {
+	t.Helper()
+	for i := 0; i < 100; i++ {
+		if data, err := os.ReadFile(path); err == nil && len(data) > 0 {
+			return string(data)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("%s was not written", path)
+	return ""
+}
+
+func TestHookRunner_RunsHookWithEnv(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks in this test are shell scripts")
+	}
+	dir := t.TempDir()
+	out := filepath.Join(dir, "out")
+	writeHook(t, dir, domain.EventWorkSessionStart, "env | grep ^KAREDORO_ | sort > "+out)
+	
+	service := NewSessionService()
+	NewHookRunner(dir, time.Second).Attach(service)
+	service.StartWorkSession()
+	
+	env := waitForFile(t, out)
+	for _, expected := range []string{
+		"KAREDORO_EVENT=work_session_start",
+		"KAREDORO_STATE=WorkSession",
+		"KAREDORO_SESSION_TYPE=Work",
+		"KAREDORO_REMAINING=1500",
+	} {
+		if !strings.Contains(env, expected) {
+			t.Errorf("Hook environment should contain %s, got:\n%s", expected, env)
+		}
+	}
+}
+
+func TestHookRunner_Timeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks in this test are shell scripts")
+	}
+	dir := t.TempDir()
+	out := filepath.Join(dir, "out")
+	writeHook(t, dir, domain.EventWorkSessionStart, "sleep 10")
+	writeHook(t, dir, domain.EventSessionPause, "echo paused > "+out)
+	
+	// Not executable, so it must be ignored
+	os.WriteFile(filepath.Join(dir, domain.EventSessionResume), []byte("#!/bin/sh\nexit 1\n"), 0644)
+	
+	service := NewSessionService()
+	NewHookRunner(dir, 100*time.Millisecond).Attach(service)
+	
+	start := time.Now()
+	service.StartWorkSession()
+	service.PauseSession()
+	service.ResumeSession()
+	if time.Since(start) > 50*time.Millisecond {
+		t.Error("Hooks should not block the session")
+	}
+	
+	// The slow hook is killed, after which the next one runs
+	if got := waitForFile(t, out); strings.TrimSpace(got) != "paused" {
+		t.Errorf("Expected the pause hook to run, got %q", got)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Error("Slow hook should have been killed at the timeout")
+	}
+}
+
+func TestHooksDir(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("the config directory follows XDG_CONFIG_HOME on Linux only")
+	}
+	config := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", config)
+	
+	dir, err := HooksDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(config, "karedoro", "hooks"); dir != want {
+		t.Errorf("Expected hooks in %s, got %s", want, dir)
+	}
+}
@@ -0,0 +1,95 @@
+package application
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Actions of the window that can be bound to keys.
+const (
+	ActionStartWork       = "start_work"
+	ActionStartBreak      = "start_break"
+	ActionStartFlow       = "start_flow"
+	ActionSkipBreak       = "skip_break"
+	ActionKeepGoing       = "keep_going"
+	ActionPause           = "pause"
+	ActionExtend          = "extend"
+	ActionAbandon         = "abandon"
+	ActionStopFlow        = "stop_flow"
+	ActionStopOvertime    = "stop_overtime"
+	ActionCancelAutoStart = "cancel_auto_start"
+	ActionSettings        = "settings"
+	ActionTheme           = "theme"
+	ActionMiniMode        = "mini_mode"
+	ActionStats           = "stats"
+)
+
+// Keymap binds the window's actions to keys. Each action lists one or more
+// keys: a key name such as "W", "Space", "Enter", "Escape", "Delete" or
+// "F1", or a single character such as "+" that matches when it is typed.
+// An action only applies where it makes sense, so one key may serve
+// actions that never apply at the same time, such as S for skipping a
+// break on the overlay and opening the settings on the main screen.
+type Keymap map[string][]string
+
+// DefaultKeymap matches the keys of the terminal UI where they overlap.
+func DefaultKeymap() Keymap {
+	return Keymap{
+		ActionStartWork:       {"W"},
+		ActionStartBreak:      {"B"},
+		ActionStartFlow:       {"F"},
+		ActionSkipBreak:       {"S"},
+		ActionKeepGoing:       {"K"},
+		ActionPause:           {"Space"},
+		ActionExtend:          {"+", "KPAdd"},
+		ActionAbandon:         {"Delete"},
+		ActionStopFlow:        {"Enter"},
+		ActionStopOvertime:    {"Escape"},
+		ActionCancelAutoStart: {"Escape"},
+		ActionSettings:        {"S"},
+		ActionTheme:           {"T"},
+		ActionMiniMode:        {"M"},
+		ActionStats:           {"I"},
+	}
+}
+
+// Validate reports actions that do not exist and empty key names. Whether a
+// key name exists is up to the window, which ignores unknown ones.
+func (k Keymap) Validate() error {
+	known := DefaultKeymap()
+	for action, keys := range k {
+		if _, ok := known[action]; !ok {
+			return fmt.Errorf("unknown key action %q (known: %s)", action, strings.Join(known.Actions(), ", "))
+		}
+		for _, key := range keys {
+			if strings.TrimSpace(key) == "" {
+				return fmt.Errorf("empty key for action %q", action)
+			}
+		}
+	}
+	return nil
+}
+
+// Actions lists the bound actions in order.
+func (k Keymap) Actions() []string {
+	actions := make([]string, 0, len(k))
+	for action := range k {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+	return actions
+}
+
+// Label is how the first key of action is shown in hints, such as "SPACE";
+// empty when the action has no key.
+func (k Keymap) Label(action string) string {
+	keys := k[action]
+	if len(keys) == 0 {
+		return ""
+	}
+	if strings.EqualFold(keys[0], "Escape") {
+		return "ESC"
+	}
+	return strings.ToUpper(keys[0])
+}
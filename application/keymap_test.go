@@ -0,0 +1,66 @@
+package application
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestKeymap_Validate(t *testing.T) {
+	if err := DefaultKeymap().Validate(); err != nil {
+		t.Errorf("Expected the default keymap to be valid, got %v", err)
+	}
+	
+	if err := (Keymap{"dance": {"D"}}).Validate(); err == nil {
+		t.Error("Expected an unknown action to be rejected")
+	}
+	if err := (Keymap{ActionPause: {" "}}).Validate(); err == nil {
+		t.Error("Expected an empty key to be rejected")
+	}
+	if err := (Keymap{ActionPause: {}}).Validate(); err != nil {
+		t.Errorf("Expected an action without keys to be allowed, got %v", err)
+	}
+}
+
+func TestKeymap_Label(t *testing.T) {
+	keymap := Keymap{
+		ActionPause:        {"Space"},
+		ActionStopOvertime: {"escape"},
+		ActionExtend:       {"+", "KPAdd"},
+		ActionAbandon:      {},
+	}
+	tests := map[string]string{
+		ActionPause:        "SPACE",
+		ActionStopOvertime: "ESC",
+		ActionExtend:       "+",
+		ActionAbandon:      "",
+		ActionTheme:        "",
+	}
+	for action, want := range tests {
+		if got := keymap.Label(action); got != want {
+			t.Errorf("Label(%s): expected %q, got %q", action, want, got)
+		}
+	}
+}
+
+func TestConfig_KeysKeepDefaults(t *testing.T) {
+	config := DefaultConfig()
+	if err := json.Unmarshal([]byte(`{"keys": {"pause": ["P"], "abandon": []}}`), config); err != nil {
+		t.Fatal(err)
+	}
+	
+	if got := config.Keys[ActionPause]; !reflect.DeepEqual(got, []string{"P"}) {
+		t.Errorf("Expected pause on P, got %v", got)
+	}
+	if got := config.Keys[ActionAbandon]; len(got) != 0 {
+		t.Errorf("Expected abandon to be unbound, got %v", got)
+	}
+	if got := config.Keys[ActionStartWork]; !reflect.DeepEqual(got, []string{"W"}) {
+		t.Errorf("Expected actions left out to keep their defaults, got %v", got)
+	}
+	
+	config.Keys["dance"] = []string{"D"}
+	if err := config.Validate(); err == nil {
+		t.Error("Expected the config to reject an unknown action")
+	}
+}
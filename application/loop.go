@@ -0,0 +1,82 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// LoopInterval is how often a Loop updates the session.
+const LoopInterval = 100 * time.Millisecond
+
+// ErrLoopStopped is returned by Loop.Do once the loop is no longer running.
+var ErrLoopStopped = errors.New("session loop stopped")
+
+// Loop serializes access to a SessionService, which is not safe for
+// concurrent use. Without a UI, Run updates the session on a fixed tick and
+// runs calls from other goroutines between ticks.
+type Loop struct {
+	sessionService *SessionService
+	interval       time.Duration
+	calls          chan func()
+	stopped        chan struct{}
+}
+
+func NewLoop(sessionService *SessionService, interval time.Duration) *Loop {
+	return &Loop{
+		sessionService: sessionService,
+		interval:       interval,
+		calls:          make(chan func()),
+		stopped:        make(chan struct{}),
+	}
+}
+
+// Run updates the session until ctx is cancelled.
+func (l *Loop) Run(ctx context.Context) {
+	defer close(l.stopped)
+	
+	ticker := time.NewTicker(l.interval)
+	defer ticker.Stop()
+	
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			l.sessionService.Update()
+		case call := <-l.calls:
+			call()
+		}
+	}
+}
+
+// RunPending runs the calls queued by Do without waiting for more. Front-ends
+// that update the session themselves, such as the ebiten game loop, call it
+// from their own update instead of running the loop.
+func (l *Loop) RunPending() {
+	for {
+		select {
+		case call := <-l.calls:
+			call()
+		default:
+			return
+		}
+	}
+}
+
+// Do runs fn on the loop's goroutine and waits for it to finish.
+func (l *Loop) Do(fn func(*SessionService)) error {
+	done := make(chan struct{})
+	call := func() {
+		defer close(done)
+		fn(l.sessionService)
+	}
+	
+	select {
+	case l.calls <- call:
+	case <-l.stopped:
+		return ErrLoopStopped
+	}
+	<-done
+	return nil
+}
@@ -0,0 +1,35 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"karedoro/domain"
+)
+
+func TestLoop_DoRunsOnLoop(t *testing.T) {
+	loop := NewLoop(NewSessionService(), time.Millisecond)
+	
+	ctx, cancel := context.WithCancel(context.Background())
+	go loop.Run(ctx)
+	
+	var state domain.SessionState
+	err := loop.Do(func(sessionService *SessionService) {
+		sessionService.StartWorkSession()
+		state = sessionService.GetSession().GetState()
+	})
+	if err != nil {
+		t.Fatalf("Do should not return error, got %v", err)
+	}
+	if state != domain.WorkSession {
+		t.Errorf("Expected WorkSession, got %v", state)
+	}
+	
+	cancel()
+	time.Sleep(10 * time.Millisecond)
+	if err := loop.Do(func(*SessionService) {}); !errors.Is(err, ErrLoopStopped) {
+		t.Errorf("Expected ErrLoopStopped after cancel, got %v", err)
+	}
+}
@@ -0,0 +1,140 @@
+package application
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"karedoro/domain"
+)
+
+// MetricsContentType is the Prometheus text exposition format written by
+// Metrics.Write.
+const MetricsContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Feedback failure kinds, as reported to Metrics.RecordFailure.
+const (
+	FailureAudio        = "audio"
+	FailureNotification = "notification"
+)
+
+// metricStates are the values of the karedoro_state gauge.
+var metricStates = []domain.SessionState{
+	domain.Idle,
+	domain.WorkSession,
+	domain.BreakSession,
+	domain.FlowSession,
+	domain.Overtime,
+}
+
+// Metrics counts session events and feedback failures for the /metrics
+// endpoint. Counters are updated from the session's goroutine and read from
+// HTTP handlers, so they are guarded by a mutex.
+type Metrics struct {
+	mu        sync.Mutex
+	completed map[string]int
+	warnings  map[string]int
+	failures  map[string]int
+	skipped   int
+	pauses    int
+	abandoned int
+	extended  int
+}
+
+func NewMetrics() *Metrics {
+	return &Metrics{
+		completed: map[string]int{"work": 0, "break": 0, "flow": 0},
+		warnings:  make(map[string]int),
+		failures:  map[string]int{FailureAudio: 0, FailureNotification: 0},
+	}
+}
+
+// Attach counts the session's events.
+func (m *Metrics) Attach(sessionService *SessionService) {
+	sessionService.AddEventListener(func(event string) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		
+		switch event {
+		case domain.EventWorkSessionEnd:
+			m.completed["work"]++
+		case domain.EventBreakSessionEnd:
+			m.completed["break"]++
+		case domain.EventFlowSessionEnd:
+			m.completed["flow"]++
+		case domain.EventBreakSkipped:
+			m.skipped++
+		case domain.EventSessionPause:
+			m.pauses++
+		case domain.EventSessionAbandon:
+			m.abandoned++
+		case domain.EventSessionExtended:
+			m.extended++
+		case domain.EventWarningGentle, domain.EventWarningNotice, domain.EventWarningUrgent, domain.EventWarningCritical:
+			m.warnings[strings.TrimPrefix(event, "warning_")]++
+		}
+	})
+}
+
+// RecordFailure counts a failed sound or notification. It matches
+// FeedbackHandler.SetErrorHandler.
+func (m *Metrics) RecordFailure(kind string, err error) {
+	if err == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.failures[kind]++
+}
+
+// Write renders the counters and the gauges for status.
+func (m *Metrics) Write(w io.Writer, status Status) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	
+	var b strings.Builder
+	writeLabeled(&b, "karedoro_sessions_completed_total", "counter", "Sessions that ran to the end, by type.", "type", m.completed)
+	writeMetric(&b, "karedoro_breaks_skipped_total", "counter", "Due breaks that were skipped.", m.skipped)
+	writeLabeled(&b, "karedoro_warnings_total", "counter", "Idle warnings fired, by severity.", "severity", m.warnings)
+	writeMetric(&b, "karedoro_pauses_total", "counter", "Times a session was paused.", m.pauses)
+	writeMetric(&b, "karedoro_sessions_abandoned_total", "counter", "Sessions stopped before the end.", m.abandoned)
+	writeMetric(&b, "karedoro_extensions_total", "counter", "Times a session was extended.", m.extended)
+	writeLabeled(&b, "karedoro_feedback_failures_total", "counter", "Sounds and notifications that failed, by kind.", "kind", m.failures)
+	
+	states := make(map[string]int)
+	for _, state := range metricStates {
+		states[state.String()] = 0
+	}
+	states[status.State] = 1
+	writeLabeled(&b, "karedoro_state", "gauge", "1 for the current session state.", "state", states)
+	
+	paused := 0
+	if status.Paused {
+		paused = 1
+	}
+	writeMetric(&b, "karedoro_paused", "gauge", "1 while the session is paused.", paused)
+	writeMetric(&b, "karedoro_remaining_seconds", "gauge", "Seconds left in the current countdown.", status.Remaining)
+	writeMetric(&b, "karedoro_elapsed_seconds", "gauge", "Seconds elapsed in the current session.", status.Elapsed)
+	
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeMetric(b *strings.Builder, name, kind, help string, value int) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n%s %d\n", name, help, name, kind, name, value)
+}
+
+func writeLabeled(b *strings.Builder, name, kind, help, label string, values map[string]int) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+	
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(b, "%s{%s=%q} %d\n", name, label, key, values[key])
+	}
+}
@@ -0,0 +1,104 @@
+package application
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"karedoro/domain"
+)
+
+// failingFeedback is an audio player and notification sender whose every
+// call fails.
+type failingFeedback struct{}
+
+var errFeedback = errors.New("no device")
+
+func (failingFeedback) PlayStartSound() error                 { return errFeedback }
+func (failingFeedback) PlayEndSound() error                   { return errFeedback }
+func (failingFeedback) PlayWarningSound() error               { return errFeedback }
+func (failingFeedback) PlayChimeSound() error                 { return errFeedback }
+func (failingFeedback) PlayAlarmSound() error                 { return errFeedback }
+func (failingFeedback) PlayBeep(float64, time.Duration) error { return errFeedback }
+func (failingFeedback) IsReady() bool                         { return false }
+func (failingFeedback) ShowWorkSessionStart() error           { return errFeedback }
+func (failingFeedback) ShowBreakSessionStart() error          { return errFeedback }
+func (failingFeedback) ShowWorkSessionEnd() error             { return errFeedback }
+func (failingFeedback) ShowBreakSessionEnd() error            { return errFeedback }
+func (failingFeedback) ShowFlowSessionStart() error           { return errFeedback }
+func (failingFeedback) ShowFlowSessionEnd() error             { return errFeedback }
+func (failingFeedback) ShowWarning() error                    { return errFeedback }
+func (failingFeedback) ShowSessionPaused() error              { return errFeedback }
+func (failingFeedback) ShowSessionResumed() error             { return errFeedback }
+func (failingFeedback) ShowPauseReminder() error              { return errFeedback }
+func (failingFeedback) ShowSessionAbandoned() error           { return errFeedback }
+
+func TestMetrics_CountsEventsAndFailures(t *testing.T) {
+	service := NewSessionService()
+	service.GetSession().SetDurations(20*time.Millisecond, 20*time.Millisecond)
+	
+	metrics := NewMetrics()
+	metrics.Attach(service)
+	feedback := NewFeedbackHandler(failingFeedback{}, failingFeedback{})
+	feedback.SetErrorHandler(metrics.RecordFailure)
+	feedback.Attach(service)
+	
+	service.StartWorkSession()
+	service.PauseSession()
+	service.ResumeSession()
+	time.Sleep(30 * time.Millisecond)
+	service.Update()
+	service.SkipBreak()
+	service.AbandonSession()
+	
+	var b strings.Builder
+	if err := metrics.Write(&b, service.Status()); err != nil {
+		t.Fatalf("Write should not return error, got %v", err)
+	}
+	output := b.String()
+	
+	for _, expected := range []string{
+		"# TYPE karedoro_sessions_completed_total counter",
+		`karedoro_sessions_completed_total{type="work"} 1`,
+		`karedoro_sessions_completed_total{type="break"} 0`,
+		"karedoro_breaks_skipped_total 1",
+		"karedoro_pauses_total 1",
+		"karedoro_sessions_abandoned_total 1",
+		// start, pause, resume, end, the second start and abandon each give feedback
+		`karedoro_feedback_failures_total{kind="audio"} 6`,
+		`karedoro_feedback_failures_total{kind="notification"} 6`,
+		`karedoro_state{state="Idle"} 1`,
+		`karedoro_state{state="WorkSession"} 0`,
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Metrics should contain %q, got:\n%s", expected, output)
+		}
+	}
+}
+
+func TestMetrics_CountsWarningsBySeverity(t *testing.T) {
+	service := NewSessionService()
+	service.GetSession().SetDurations(10*time.Millisecond, 10*time.Millisecond)
+	service.GetSession().SetWarningLadder([]domain.WarningStep{
+		{After: 10 * time.Millisecond, Severity: domain.WarningGentle},
+	}, 10*time.Millisecond)
+	
+	metrics := NewMetrics()
+	metrics.Attach(service)
+	
+	service.StartWorkSession()
+	time.Sleep(15 * time.Millisecond)
+	service.Update()
+	time.Sleep(15 * time.Millisecond)
+	
+	// The warning timer catches up on one update and fires on the next
+	service.Update()
+	service.Update()
+	
+	var b strings.Builder
+	metrics.Write(&b, service.Status())
+	if !strings.Contains(b.String(), `karedoro_warnings_total{severity="gentle"} 1`) {
+		t.Errorf("Expected one gentle warning, got:\n%s", b.String())
+	}
+}
@@ -1,19 +1,107 @@
 package application
 
 import (
+	"path/filepath"
+	"time"
+
 	"github.com/gen2brain/beeep"
+
+	"karedoro/domain"
+	"karedoro/i18n"
+)
+
+// Urgency is the urgency level of a notification, as in the freedesktop
+// notification spec.
+type Urgency byte
+
+const (
+	UrgencyLow Urgency = iota
+	UrgencyNormal
+	UrgencyCritical
 )
 
+func (u Urgency) String() string {
+	switch u {
+	case UrgencyLow:
+		return "low"
+	case UrgencyCritical:
+		return "critical"
+	default:
+		return "normal"
+	}
+}
+
+// NeverExpire keeps a notification on screen until the user dismisses it.
+const NeverExpire time.Duration = -1
+
+// Expiry of notifications that only report what happened.
+const infoExpire = 5 * time.Second
+
+// notificationTag is shared by all session notifications, so each one
+// replaces the last instead of stacking up.
+const notificationTag = "session"
+
+// NotificationAction is a button on a notification.
+type NotificationAction struct {
+	ID    string
+	Label string
+	
+	// Run is called on the session's goroutine when the button is pressed.
+	Run func(*SessionService) error
+}
+
+// Notification is a desktop notification.
+type Notification struct {
+	Title   string
+	Body    string
+	Urgency Urgency
+	
+	// Tag groups notifications that replace each other; empty never replaces.
+	Tag string
+	
+	// Expire is how long the notification stays; zero leaves it to the
+	// notification server.
+	Expire time.Duration
+	
+	Actions []NotificationAction
+}
+
+// Notifier displays notifications on the desktop.
+type Notifier interface {
+	Notify(notification Notification) error
+}
+
+// beeepNotifier shows plain notifications through beeep. It has no buttons,
+// replacement or expiry, so it ignores those.
+type beeepNotifier struct{}
+
+func (beeepNotifier) Notify(notification Notification) error {
+	return beeep.Notify(notification.Title, notification.Body, "")
+}
+
 type NotificationService struct {
 	appName string
 	enabled bool
+	
+	// desktop is the desktop sink; sinks routes to all configured sinks.
+	desktop Notifier
+	sinks   Notifier
+	
+	// messages renders the texts, filled in from the session and today's stats.
+	messages       *i18n.Catalog
+	sessionService *SessionService
+	stats          *StatsService
 }
 
 func NewNotificationService() *NotificationService {
-	return &NotificationService{
+	n := &NotificationService{
 		appName: "karedoro",
 		enabled: true,
+		desktop:  beeepNotifier{},
+		messages: i18n.Default(),
 	}
+	n.sinks = desktopSink{n}
+	return n
 }
 
 func (n *NotificationService) SetEnabled(enabled bool) {
@@ -24,88 +112,215 @@ func (n *NotificationService) IsEnabled() bool {
 	return n.enabled
 }
 
-func (n *NotificationService) ShowWorkSessionStart() error {
-	if !n.enabled {
-		return nil
+// SetNotifier replaces the desktop notifier, for example with one that
+// supports action buttons.
+func (n *NotificationService) SetNotifier(notifier Notifier) {
+	n.desktop = notifier
+}
+
+// SetMessages renders the texts from messages, filling in durations from
+// sessionService and today's counts from stats, which may be nil.
+func (n *NotificationService) SetMessages(messages *i18n.Catalog, sessionService *SessionService, stats *StatsService) {
+	n.messages = messages
+	n.sessionService = sessionService
+	n.stats = stats
+}
+
+// Configure builds the sinks chosen in config. dir holds the default log file.
+func (n *NotificationService) Configure(config NotificationsConfig, dir string) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+	if config.LogFile == "" {
+		config.LogFile = filepath.Join(dir, "notifications.log")
+	}
+	
+	var chain NotifierChain
+	for _, name := range config.Chain {
+		sink, err := n.newSink(name, config)
+		if err != nil {
+			return err
+		}
+		chain = append(chain, sink)
 	}
 	
-	return beeep.Notify(
-		n.appName,
-		"WORK SESSION STARTED! Focus for 25 minutes - NO DISTRACTIONS!",
-		"",
-	)
+	var fanOut NotifierFanOut
+	if len(chain) > 0 {
+		fanOut = append(fanOut, chain)
+	}
+	for _, name := range config.Sinks {
+		sink, err := n.newSink(name, config)
+		if err != nil {
+			return err
+		}
+		fanOut = append(fanOut, sink)
+	}
+	n.sinks = fanOut
+	return nil
 }
 
-func (n *NotificationService) ShowBreakSessionStart() error {
-	if !n.enabled {
-		return nil
+func (n *NotificationService) newSink(name string, config NotificationsConfig) (Notifier, error) {
+	if name == SinkDesktop {
+		return desktopSink{n}, nil
 	}
-	
-	return beeep.Notify(
-		n.appName,
-		"BREAK SESSION STARTED! Relax for 5 minutes - You earned it!",
-		"",
-	)
+	return notificationSinks[name](config)
 }
 
-func (n *NotificationService) ShowWorkSessionEnd() error {
+// desktopSink forwards to the current desktop notifier, so that a later
+// SetNotifier also applies to the configured sinks.
+type desktopSink struct {
+	n *NotificationService
+}
+
+func (d desktopSink) Notify(notification Notification) error {
+	return d.n.desktop.Notify(notification)
+}
+
+func (n *NotificationService) show(notification Notification) error {
 	if !n.enabled {
 		return nil
 	}
 	
-	return beeep.Notify(
-		n.appName,
-		"POMODORO COMPLETE! You MUST take a break now - No skipping!",
-		"",
-	)
+	notification.Title = n.appName
+	notification.Tag = notificationTag
+	return n.sinks.Notify(notification)
 }
 
-func (n *NotificationService) ShowBreakSessionEnd() error {
-	if !n.enabled {
-		return nil
+// text renders a message about a length of time, such as the session that
+// starts or the break that is due.
+func (n *NotificationService) text(key string, duration time.Duration) string {
+	data := i18n.Data{Duration: i18n.Duration(duration)}
+	if n.stats != nil {
+		data.CompletedToday = n.stats.Today().WorkSessionsCompleted
+		data.SkippedToday = n.stats.Today().BreaksSkipped
 	}
-	
-	return beeep.Notify(
-		n.appName,
-		"BREAK OVER! Time to get back to work - Start your session NOW!",
-		"",
-	)
+	return n.messages.Text(key, data)
 }
 
-func (n *NotificationService) ShowWarning() error {
-	if !n.enabled {
+// session returns the session the messages describe; nil before SetMessages.
+func (n *NotificationService) session() *domain.Session {
+	if n.sessionService == nil {
 		return nil
 	}
-	
-	return beeep.Notify(
-		n.appName,
-		"WARNING! You haven't started your next session! FOLLOW THE POMODORO TECHNIQUE!",
-		"",
-	)
+	return n.sessionService.GetSession()
 }
 
-func (n *NotificationService) ShowSessionPaused() error {
-	if !n.enabled {
-		return nil
+// 休憩へ進むためのボタン
+func (n *NotificationService) breakActions() []NotificationAction {
+	return []NotificationAction{
+		{ID: "start-break", Label: n.text("action.start_break", 0), Run: (*SessionService).StartBreakSession},
+		{ID: "skip-break", Label: n.text("action.skip", 0), Run: (*SessionService).SkipBreak},
 	}
-	
-	return beeep.Notify(
-		n.appName,
-		"Session paused",
-		"",
-	)
 }
 
-func (n *NotificationService) ShowSessionResumed() error {
-	if !n.enabled {
-		return nil
+// remaining is the time left in the session that has just started.
+func (n *NotificationService) remaining() time.Duration {
+	if session := n.session(); session != nil {
+		return session.GetTimeRemaining()
 	}
-	
-	return beeep.Notify(
-		n.appName,
-		"Session resumed",
-		"",
-	)
+	return 0
+}
+
+// nextBreak is the break that is due after a work or flow session.
+func (n *NotificationService) nextBreak() time.Duration {
+	if session := n.session(); session != nil {
+		return session.NextBreakDuration()
+	}
+	return 0
+}
+
+func (n *NotificationService) ShowWorkSessionStart() error {
+	return n.show(Notification{
+		Body:    n.text("notify.work_start", n.remaining()),
+		Urgency: UrgencyLow,
+		Expire:  infoExpire,
+	})
+}
+
+func (n *NotificationService) ShowBreakSessionStart() error {
+	return n.show(Notification{
+		Body:    n.text("notify.break_start", n.remaining()),
+		Urgency: UrgencyLow,
+		Expire:  infoExpire,
+	})
+}
+
+func (n *NotificationService) ShowWorkSessionEnd() error {
+	return n.show(Notification{
+		Body:    n.text("notify.work_end", n.nextBreak()),
+		Urgency: UrgencyNormal,
+		Expire:  NeverExpire,
+		Actions: n.breakActions(),
+	})
+}
+
+func (n *NotificationService) ShowBreakSessionEnd() error {
+	return n.show(Notification{
+		Body:    n.text("notify.break_end", 0),
+		Urgency: UrgencyNormal,
+		Expire:  NeverExpire,
+		Actions: []NotificationAction{
+			{ID: "start-work", Label: n.text("action.start_work", 0), Run: (*SessionService).StartNextSession},
+		},
+	})
+}
+
+func (n *NotificationService) ShowFlowSessionStart() error {
+	return n.show(Notification{
+		Body:    n.text("notify.flow_start", 0),
+		Urgency: UrgencyLow,
+		Expire:  infoExpire,
+	})
+}
+
+func (n *NotificationService) ShowFlowSessionEnd() error {
+	return n.show(Notification{
+		Body:    n.text("notify.flow_end", n.nextBreak()),
+		Urgency: UrgencyNormal,
+		Expire:  NeverExpire,
+		Actions: n.breakActions(),
+	})
+}
+
+func (n *NotificationService) ShowWarning() error {
+	return n.show(Notification{
+		Body:    n.text("notify.warning", 0),
+		Urgency: UrgencyCritical,
+		Expire:  NeverExpire,
+		Actions: []NotificationAction{
+			{ID: "start-next", Label: n.text("action.start_now", 0), Run: (*SessionService).StartNextSession},
+		},
+	})
+}
+
+func (n *NotificationService) ShowSessionPaused() error {
+	return n.show(Notification{
+		Body:    n.text("notify.paused", 0),
+		Urgency: UrgencyLow,
+		Expire:  infoExpire,
+	})
+}
+
+func (n *NotificationService) ShowSessionResumed() error {
+	return n.show(Notification{
+		Body:    n.text("notify.resumed", 0),
+		Urgency: UrgencyLow,
+		Expire:  infoExpire,
+	})
+}
+
+func (n *NotificationService) ShowPauseReminder() error {
+	return n.show(Notification{
+		Body:    n.text("notify.pause_reminder", 0),
+		Urgency: UrgencyNormal,
+	})
+}
+
+func (n *NotificationService) ShowSessionAbandoned() error {
+	return n.show(Notification{
+		Body:    n.text("notify.abandoned", 0),
+		Urgency: UrgencyNormal,
+	})
 }
 
 func (n *NotificationService) ShowCustomMessage(title, message string) error {
@@ -113,5 +328,5 @@ func (n *NotificationService) ShowCustomMessage(title, message string) error {
 		return nil
 	}
 	
-	return beeep.Notify(title, message, "")
-}
\ No newline at end of file
+	return n.sinks.Notify(Notification{Title: title, Body: message, Urgency: UrgencyNormal})
+}
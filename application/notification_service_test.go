@@ -0,0 +1,125 @@
+package application
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"karedoro/domain"
+	"karedoro/i18n"
+)
+
+type recordingNotifier struct {
+	notifications []Notification
+}
+
+func (r *recordingNotifier) Notify(notification Notification) error {
+	r.notifications = append(r.notifications, notification)
+	return nil
+}
+
+func findAction(notification Notification, id string) *NotificationAction {
+	for i := range notification.Actions {
+		if notification.Actions[i].ID == id {
+			return &notification.Actions[i]
+		}
+	}
+	return nil
+}
+
+func TestNotificationService_WorkEndActions(t *testing.T) {
+	notifier := &recordingNotifier{}
+	n := NewNotificationService()
+	n.SetNotifier(notifier)
+	
+	if err := n.ShowWorkSessionEnd(); err != nil {
+		t.Fatalf("ShowWorkSessionEnd should not return error, got %v", err)
+	}
+	if len(notifier.notifications) != 1 {
+		t.Fatalf("Expected 1 notification, got %d", len(notifier.notifications))
+	}
+	notification := notifier.notifications[0]
+	if notification.Title != "karedoro" || notification.Tag == "" {
+		t.Errorf("Expected a tagged karedoro notification, got %+v", notification)
+	}
+	if notification.Expire != NeverExpire {
+		t.Errorf("Expected work end to stay until dismissed, got %v", notification.Expire)
+	}
+	
+	sessionService := NewSessionService()
+	config := DefaultConfig()
+	config.WorkDuration = 20 * time.Millisecond
+	sessionService.Configure(config)
+	sessionService.StartWorkSession()
+	time.Sleep(30 * time.Millisecond)
+	sessionService.Update()
+	
+	startBreak := findAction(notification, "start-break")
+	if startBreak == nil || findAction(notification, "skip-break") == nil {
+		t.Fatalf("Expected Start Break and Skip buttons, got %+v", notification.Actions)
+	}
+	if err := startBreak.Run(sessionService); err != nil {
+		t.Fatalf("Start Break should not return error, got %v", err)
+	}
+	if sessionService.GetSession().GetState() != domain.BreakSession {
+		t.Errorf("Expected BreakSession, got %v", sessionService.GetSession().GetState())
+	}
+}
+
+func TestNotificationService_WarningsReplaceEachOther(t *testing.T) {
+	notifier := &recordingNotifier{}
+	n := NewNotificationService()
+	n.SetNotifier(notifier)
+	
+	n.ShowWarning()
+	n.ShowWarning()
+	
+	first, second := notifier.notifications[0], notifier.notifications[1]
+	if first.Tag != second.Tag {
+		t.Errorf("Expected repeated warnings to share a tag, got %q and %q", first.Tag, second.Tag)
+	}
+	if first.Urgency != UrgencyCritical {
+		t.Errorf("Expected critical urgency, got %v", first.Urgency)
+	}
+	if findAction(first, "start-next") == nil {
+		t.Errorf("Expected a Start now button, got %+v", first.Actions)
+	}
+}
+
+func TestNotificationService_Disabled(t *testing.T) {
+	notifier := &recordingNotifier{}
+	n := NewNotificationService()
+	n.SetNotifier(notifier)
+	n.SetEnabled(false)
+	
+	n.ShowWarning()
+	n.ShowCustomMessage("title", "message")
+	
+	if len(notifier.notifications) != 0 {
+		t.Errorf("Expected no notifications while disabled, got %d", len(notifier.notifications))
+	}
+}
+
+func TestNotificationService_MessagesFollowConfig(t *testing.T) {
+	notifier := &recordingNotifier{}
+	n := NewNotificationService()
+	n.SetNotifier(notifier)
+	
+	sessionService := NewSessionService()
+	config := DefaultConfig()
+	config.WorkDuration = 50 * time.Minute
+	sessionService.Configure(config)
+	messages, err := i18n.Load("ja", "")
+	if err != nil {
+		t.Fatalf("Load should not return error, got %v", err)
+	}
+	n.SetMessages(messages, sessionService, nil)
+	
+	sessionService.StartWorkSession()
+	n.ShowWorkSessionStart()
+	
+	body := notifier.notifications[0].Body
+	if !strings.Contains(body, "50分間") {
+		t.Errorf("Expected the configured 50 minutes in Japanese, got %q", body)
+	}
+}
@@ -0,0 +1,172 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Built-in notification sinks.
+const (
+	SinkDesktop  = "desktop"
+	SinkTerminal = "terminal"
+	SinkLog      = "log"
+	SinkCommand  = "command"
+)
+
+// commandSinkTimeout bounds the notification command, which runs on the
+// session's goroutine so that its failure can fall back to the next sink.
+const commandSinkTimeout = 5 * time.Second
+
+// NotificationsConfig chooses where notifications go. Each notification is
+// shown by the first sink in Chain that succeeds, and is also sent to every
+// sink in Sinks.
+type NotificationsConfig struct {
+	// Enabled turns all notifications on or off.
+	Enabled bool `json:"enabled"`
+	
+	Chain []string `json:"chain"`
+	Sinks []string `json:"sinks"`
+	
+	// LogFile is written by the log sink; empty means notifications.log in
+	// the config directory.
+	LogFile string `json:"log_file"`
+	
+	// Command is run by the command sink, with the notification in
+	// KAREDORO_NOTIFY_TITLE, KAREDORO_NOTIFY_BODY and KAREDORO_NOTIFY_URGENCY.
+	Command []string `json:"command"`
+}
+
+// DefaultNotificationsConfig shows notifications on the desktop, or in the
+// terminal where there is no notification daemon.
+func DefaultNotificationsConfig() NotificationsConfig {
+	return NotificationsConfig{
+		Enabled: true,
+		Chain:   []string{SinkDesktop, SinkTerminal},
+	}
+}
+
+// Validate reports whether every sink is known and has what it needs.
+func (c NotificationsConfig) Validate() error {
+	for _, name := range append(append([]string(nil), c.Chain...), c.Sinks...) {
+		if name == SinkDesktop {
+			continue
+		}
+		if _, ok := notificationSinks[name]; !ok {
+			return fmt.Errorf("unknown notification sink %q (known: %s)", name, strings.Join(NotificationSinkNames(), ", "))
+		}
+		if name == SinkCommand && len(c.Command) == 0 {
+			return errors.New("notification sink \"command\" needs notifications.command")
+		}
+	}
+	return nil
+}
+
+// notificationSinkFactory builds a sink from the notification settings.
+type notificationSinkFactory func(config NotificationsConfig) (Notifier, error)
+
+// notificationSinks holds the sinks by name. The desktop sink is not here,
+// since it follows NotificationService.SetNotifier.
+var notificationSinks = map[string]notificationSinkFactory{
+	SinkTerminal: func(config NotificationsConfig) (Notifier, error) {
+		return NewTerminalSink(os.Stderr), nil
+	},
+	SinkLog: func(config NotificationsConfig) (Notifier, error) {
+		return &LogSink{path: config.LogFile}, nil
+	},
+	SinkCommand: func(config NotificationsConfig) (Notifier, error) {
+		return &CommandSink{argv: config.Command, timeout: commandSinkTimeout}, nil
+	},
+}
+
+// NotificationSinkNames lists the sinks that can be configured.
+func NotificationSinkNames() []string {
+	names := []string{SinkDesktop}
+	for name := range notificationSinks {
+		names = append(names, name)
+	}
+	sort.Strings(names[1:])
+	return names
+}
+
+// NotifierChain shows a notification with the first notifier that succeeds.
+type NotifierChain []Notifier
+
+func (c NotifierChain) Notify(notification Notification) error {
+	var errs []error
+	for _, notifier := range c {
+		err := notifier.Notify(notification)
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// NotifierFanOut sends a notification to every notifier.
+type NotifierFanOut []Notifier
+
+func (f NotifierFanOut) Notify(notification Notification) error {
+	var errs []error
+	for _, notifier := range f {
+		if err := notifier.Notify(notification); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// LogSink appends notifications to a file, which it creates readable only by
+// the user.
+type LogSink struct {
+	path string
+}
+
+func (l *LogSink) Notify(notification Notification) error {
+	if err := os.MkdirAll(filepath.Dir(l.path), 0700); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	
+	_, err = fmt.Fprintf(file, "%s [%s] %s: %s\n", time.Now().Format(time.RFC3339), notification.Urgency, notification.Title, notification.Body)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// CommandSink runs a command for each notification.
+type CommandSink struct {
+	argv    []string
+	timeout time.Duration
+}
+
+func (c *CommandSink) Notify(notification Notification) error {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+	
+	cmd := exec.CommandContext(ctx, c.argv[0], c.argv[1:]...)
+	cmd.Env = append(os.Environ(),
+		"KAREDORO_NOTIFY_TITLE="+notification.Title,
+		"KAREDORO_NOTIFY_BODY="+notification.Body,
+		"KAREDORO_NOTIFY_URGENCY="+notification.Urgency.String(),
+	)
+	cmd.WaitDelay = time.Second
+	if output, err := cmd.CombinedOutput(); err != nil {
+		if text := strings.TrimSpace(string(output)); text != "" {
+			return fmt.Errorf("notification command: %w: %s", err, text)
+		}
+		return fmt.Errorf("notification command: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package application
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+type failingNotifier struct{}
+
+func (failingNotifier) Notify(Notification) error {
+	return errors.New("no notification daemon")
+}
+
+func TestNotifierChain_FallsBack(t *testing.T) {
+	second := &recordingNotifier{}
+	chain := NotifierChain{failingNotifier{}, second, &recordingNotifier{}}
+	
+	if err := chain.Notify(Notification{Body: "hello"}); err != nil {
+		t.Fatalf("Chain should succeed through its second sink, got %v", err)
+	}
+	if len(second.notifications) != 1 {
+		t.Errorf("Expected the second sink to show the notification, got %d", len(second.notifications))
+	}
+	
+	if err := (NotifierChain{failingNotifier{}, failingNotifier{}}).Notify(Notification{}); err == nil {
+		t.Error("Chain should fail when every sink fails")
+	}
+}
+
+func TestNotifierFanOut_SendsToAll(t *testing.T) {
+	first, second := &recordingNotifier{}, &recordingNotifier{}
+	fanOut := NotifierFanOut{first, failingNotifier{}, second}
+	
+	if err := fanOut.Notify(Notification{Body: "hello"}); err == nil {
+		t.Error("Fan-out should report the failing sink")
+	}
+	if len(first.notifications) != 1 || len(second.notifications) != 1 {
+		t.Errorf("Expected both sinks to receive the notification, got %d and %d", len(first.notifications), len(second.notifications))
+	}
+}
+
+func TestNotificationService_Configure(t *testing.T) {
+	dir := t.TempDir()
+	desktop := &recordingNotifier{}
+	n := NewNotificationService()
+	n.SetNotifier(desktop)
+	
+	config := DefaultNotificationsConfig()
+	config.Sinks = []string{SinkLog}
+	if err := n.Configure(config, dir); err != nil {
+		t.Fatalf("Configure should not return error, got %v", err)
+	}
+	
+	if err := n.ShowSessionPaused(); err != nil {
+		t.Fatalf("ShowSessionPaused should not return error, got %v", err)
+	}
+	if len(desktop.notifications) != 1 {
+		t.Errorf("Expected the desktop sink to show the notification, got %d", len(desktop.notifications))
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "notifications.log"))
+	if err != nil {
+		t.Fatalf("Expected the log sink to write notifications.log: %v", err)
+	}
+	if !strings.Contains(string(data), "[low] karedoro: Session paused") {
+		t.Errorf("Unexpected log line %q", data)
+	}
+	if info, err := os.Stat(filepath.Join(dir, "notifications.log")); err == nil && runtime.GOOS != "windows" && info.Mode().Perm() != 0600 {
+		t.Errorf("Expected notifications.log to have mode 600, got %o", info.Mode().Perm())
+	}
+}
+
+func TestNotificationsConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		config NotificationsConfig
+		valid  bool
+	}{
+		{"default", DefaultNotificationsConfig(), true},
+		{"unknown sink", NotificationsConfig{Chain: []string{"pager"}}, false},
+		{"command without argv", NotificationsConfig{Sinks: []string{SinkCommand}}, false},
+		{"command", NotificationsConfig{Sinks: []string{SinkCommand}, Command: []string{"notify"}}, true},
+		{"nothing", NotificationsConfig{}, true},
+	}
+	for _, tt := range tests {
+		if err := tt.config.Validate(); (err == nil) != tt.valid {
+			t.Errorf("%s: Validate() = %v, want valid %v", tt.name, err, tt.valid)
+		}
+	}
+}
+
+func TestCommandSink(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the command in this test is a shell script")
+	}
+	out := filepath.Join(t.TempDir(), "out")
+	sink := &CommandSink{
+		argv:    []string{"sh", "-c", `printf '%s|%s' "$KAREDORO_NOTIFY_URGENCY" "$KAREDORO_NOTIFY_BODY" > "$0"`, out},
+		timeout: commandSinkTimeout,
+	}
+	
+	if err := sink.Notify(Notification{Body: "Break over", Urgency: UrgencyCritical}); err != nil {
+		t.Fatalf("Notify should not return error, got %v", err)
+	}
+	data, _ := os.ReadFile(out)
+	if string(data) != "critical|Break over" {
+		t.Errorf("Expected the command to see the notification, got %q", data)
+	}
+	
+	failing := &CommandSink{argv: []string{"sh", "-c", "echo broken >&2; exit 1"}, timeout: commandSinkTimeout}
+	if err := failing.Notify(Notification{}); err == nil || !strings.Contains(err.Error(), "broken") {
+		t.Errorf("Expected the command's output in the error, got %v", err)
+	}
+}
+
+func TestTerminalSink(t *testing.T) {
+	var buf bytes.Buffer
+	sink := &TerminalSink{out: &buf, osc: "777"}
+	
+	sink.Notify(Notification{Title: "karedoro", Body: "Break\nover"})
+	if got := buf.String(); got != "\a\x1b]777;notify;karedoro;Breakover\a" {
+		t.Errorf("Unexpected OSC 777 output %q", got)
+	}
+	
+	buf.Reset()
+	sink.osc = ""
+	sink.Notify(Notification{Title: "karedoro", Body: "Break over"})
+	if got := buf.String(); got != "\a" {
+		t.Errorf("Expected only the bell, got %q", got)
+	}
+	
+	file, err := os.Create(filepath.Join(t.TempDir(), "not-a-terminal"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if err := NewTerminalSink(file).Notify(Notification{}); err != errNotTerminal {
+		t.Errorf("Expected errNotTerminal for a regular file, got %v", err)
+	}
+}
+
+func TestTerminalOSC(t *testing.T) {
+	tests := []struct {
+		env  map[string]string
+		want string
+	}{
+		{map[string]string{"TERM_PROGRAM": "iTerm.app"}, "9"},
+		{map[string]string{"WT_SESSION": "1"}, "9"},
+		{map[string]string{"TERM": "xterm-kitty"}, "9"},
+		{map[string]string{"TERM": "rxvt-unicode-256color"}, "777"},
+		{map[string]string{"TERM": "foot"}, "777"},
+		{map[string]string{"TERM": "xterm-256color"}, ""},
+	}
+	for _, tt := range tests {
+		getenv := func(key string) string { return tt.env[key] }
+		if got := terminalOSC(getenv); got != tt.want {
+			t.Errorf("terminalOSC(%v) = %q, want %q", tt.env, got, tt.want)
+		}
+	}
+}
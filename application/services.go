@@ -1,7 +1,10 @@
 package application
 
 import (
+	"log"
+
 	"karedoro/domain"
+	"karedoro/i18n"
 )
 
 // Services provides a container for all application services with dependency injection.
@@ -10,6 +13,17 @@ type Services struct {
 	Audio        domain.AudioPlayer
 	Notification domain.NotificationSender
 	Config       *ConfigService
+	Stats        *StatsService
+	
+	// Messages renders notification and screen texts in the configured locale.
+	Messages *i18n.Catalog
+	
+	// Loop serializes calls into Session from goroutines other than the one
+	// that updates it.
+	Loop *Loop
+	
+	// Metrics is set by AttachIntegrations when the metrics endpoint is enabled.
+	Metrics *Metrics
 }
 
 // NewServices creates a new Services container with all dependencies wired up.
@@ -18,12 +32,22 @@ func NewServices() *Services {
 	notificationService := NewNotificationService()
 	sessionService := NewSessionService()
 	configService := NewConfigService()
+	configureSession(sessionService, configService)
+	configureNotifications(notificationService, configService)
+	configureFeedback(audioService, notificationService, configService.GetConfig())
+	statsService := NewStatsService()
+	statsService.Attach(sessionService)
+	messages := loadMessages(configService)
+	notificationService.SetMessages(messages, sessionService, statsService)
 	
 	return &Services{
 		Session:      sessionService,
 		Audio:        audioService,
 		Notification: notificationService,
 		Config:       configService,
+		Stats:        statsService,
+		Messages:     messages,
+		Loop:         NewLoop(sessionService, LoopInterval),
 	}
 }
 
@@ -35,11 +59,114 @@ func NewServicesWithDependencies(
 ) *Services {
 	sessionService := NewSessionService()
 	configService := NewConfigService()
+	configureSession(sessionService, configService)
+	configureFeedback(audio, notification, configService.GetConfig())
+	statsService := NewStatsService()
+	statsService.Attach(sessionService)
 	
 	return &Services{
 		Session:      sessionService,
 		Audio:        audio,
 		Notification: notification,
 		Config:       configService,
+		Stats:        statsService,
+		Messages:     loadMessages(configService),
+		Loop:         NewLoop(sessionService, LoopInterval),
+	}
+}
+
+// ApplyConfig validates config, applies it and saves it. It must be called on
+// the session's goroutine. A running session keeps its length; changed
+// durations take effect from the next session. The notification sinks are
+// rebuilt, while the settings listed by RestartChanges are only saved.
+func (s *Services) ApplyConfig(config *Config) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+	if notificationService, ok := s.Notification.(*NotificationService); ok {
+		if err := notificationService.Configure(config.Notifications, s.Config.Dir()); err != nil {
+			return err
+		}
+	}
+	if err := s.Session.Configure(config); err != nil {
+		return err
+	}
+	configureFeedback(s.Audio, s.Notification, config)
+	return s.Config.UpdateConfig(config)
+}
+
+// AttachIntegrations attaches the subscribers that act outside karedoro on
+// session events: the user's hook scripts, the configured webhooks and, if
+// enabled, the metrics. Only the instance that owns the timer should call it.
+func (s *Services) AttachIntegrations() {
+	if s.Config == nil {
+		return
+	}
+	config := s.Config.GetConfig()
+	
+	if config.HTTP.Metrics {
+		s.Metrics = NewMetrics()
+		s.Metrics.Attach(s.Session)
+	}
+	
+	if dir, err := HooksDir(); err != nil {
+		log.Printf("Warning: hooks are disabled: %v", err)
+	} else {
+		NewHookRunner(dir, config.Hooks.Timeout).Attach(s.Session)
+	}
+	
+	webhooks, err := NewWebhooks(config.Webhooks, nil)
+	if err != nil {
+		log.Printf("Warning: %v", err)
 	}
-}
\ No newline at end of file
+	webhooks.Attach(s.Session)
+}
+
+// NewFeedbackHandler builds the handler for sounds and notifications,
+// counting their failures in Metrics when it is enabled.
+func (s *Services) NewFeedbackHandler() *FeedbackHandler {
+	feedback := NewFeedbackHandler(s.Audio, s.Notification)
+	if s.Metrics != nil {
+		feedback.SetErrorHandler(s.Metrics.RecordFailure)
+	}
+	return feedback
+}
+
+// loadMessages loads the message catalog for the configured locale with the
+// user's overrides, keeping the built-in English texts if they are invalid.
+func loadMessages(configService *ConfigService) *i18n.Catalog {
+	messages, err := i18n.Load(configService.GetConfig().Locale, configService.Dir())
+	if err != nil {
+		log.Printf("Warning: using the built-in English messages: %v", err)
+		return i18n.Default()
+	}
+	return messages
+}
+
+// configureNotifications builds the configured notification sinks, keeping
+// desktop notifications if the configuration is invalid.
+func configureNotifications(notificationService *NotificationService, configService *ConfigService) {
+	if err := notificationService.Configure(configService.GetConfig().Notifications, configService.Dir()); err != nil {
+		log.Printf("Warning: using desktop notifications only: %v", err)
+	}
+}
+
+// configureFeedback applies the sound and notification switches to the
+// built-in audio and notification services.
+func configureFeedback(audio domain.AudioPlayer, notification domain.NotificationSender, config *Config) {
+	if audioService, ok := audio.(*AudioService); ok {
+		audioService.SetVolume(config.Volume)
+		audioService.SetEnabled(config.SoundEnabled)
+	}
+	if notificationService, ok := notification.(*NotificationService); ok {
+		notificationService.SetEnabled(config.Notifications.Enabled)
+	}
+}
+
+// configureSession applies the loaded configuration to the session, keeping
+// the built-in defaults if the configuration is invalid.
+func configureSession(sessionService *SessionService, configService *ConfigService) {
+	if err := sessionService.Configure(configService.GetConfig()); err != nil {
+		log.Printf("Warning: using default session settings: %v", err)
+	}
+}
@@ -0,0 +1,53 @@
+package application
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"karedoro/domain"
+)
+
+func TestServices_ApplyConfig(t *testing.T) {
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", t.TempDir())
+	defer os.Setenv("HOME", originalHome)
+	
+	notificationService := NewNotificationService()
+	services := NewServicesWithDependencies(nil, notificationService)
+	if err := services.Session.StartWorkSession(); err != nil {
+		t.Fatalf("Failed to start work session: %v", err)
+	}
+	
+	config := *services.Config.GetConfig()
+	config.WorkDuration = 50 * time.Minute
+	config.Volume = 1.5
+	if err := services.ApplyConfig(&config); err == nil {
+		t.Fatal("Expected an out-of-range volume to be rejected")
+	}
+	if services.Session.GetSession().GetDuration(domain.Work) != 25*time.Minute {
+		t.Error("A rejected config should not be applied")
+	}
+	
+	config.Volume = 0.4
+	config.Notifications.Enabled = false
+	if err := services.ApplyConfig(&config); err != nil {
+		t.Fatalf("Failed to apply config: %v", err)
+	}
+	
+	session := services.Session.GetSession()
+	if session.GetDuration(domain.Work) != 50*time.Minute {
+		t.Errorf("Expected the next work session to last 50m, got %v", session.GetDuration(domain.Work))
+	}
+	if remaining := session.GetTimeRemaining(); remaining > 25*time.Minute {
+		t.Errorf("The running session should keep its length, got %v remaining", remaining)
+	}
+	if notificationService.IsEnabled() {
+		t.Error("Notifications should be turned off")
+	}
+	
+	saved := NewConfigService().GetConfig()
+	if saved.WorkDuration != 50*time.Minute || saved.Volume != 0.4 {
+		t.Errorf("Expected the config to be saved, got %v and %v", saved.WorkDuration, saved.Volume)
+	}
+}
@@ -1,12 +1,19 @@
 package application
 
 import (
+	"time"
+
 	"karedoro/domain"
 )
 
 type SessionService struct {
 	session *domain.Session
 	eventCallbacks map[string][]func()
+	eventListeners []func(string)
+	
+	// autoStartAnnounced is set while listeners were told of a countdown
+	// that has neither run out nor been cancelled yet.
+	autoStartAnnounced bool
 }
 
 func NewSessionService() *SessionService {
@@ -21,25 +28,67 @@ func NewSessionService() *SessionService {
 }
 
 func (s *SessionService) StartWorkSession() error {
+	skipping := s.session.IsBreakDue()
+	
 	err := s.session.StartWorkSession()
 	if err != nil {
 		return err
 	}
 	
-	s.triggerEvent(domain.EventWorkSessionStart)
+	// 開始イベントは状態遷移時に onStateChange が発火する
+	if skipping {
+		s.triggerEvent(domain.EventBreakSkipped)
+	}
 	return nil
 }
 
-func (s *SessionService) StartBreakSession() error {
-	err := s.session.StartBreakSession()
+// SkipBreak skips the due break and starts the next work or flow session.
+func (s *SessionService) SkipBreak() error {
+	if !s.session.IsBreakDue() {
+		return domain.NewSessionError("skip break", domain.ErrNoBreakDue)
+	}
+	if s.session.GetSessionType() == domain.Flow {
+		return s.StartFlowSession()
+	}
+	return s.StartWorkSession()
+}
+
+// StartFlowSession starts open-ended flowtime work.
+func (s *SessionService) StartFlowSession() error {
+	skipping := s.session.IsBreakDue()
+	
+	err := s.session.StartFlowSession()
 	if err != nil {
 		return err
 	}
 	
-	s.triggerEvent(domain.EventBreakSessionStart)
+	if skipping {
+		s.triggerEvent(domain.EventBreakSkipped)
+	}
 	return nil
 }
 
+// StopFlowSession ends flowtime work and makes the earned break due.
+func (s *SessionService) StopFlowSession() error {
+	return s.session.StopFlowSession()
+}
+
+func (s *SessionService) StartBreakSession() error {
+	return s.session.StartBreakSession()
+}
+
+// StartNextSession starts the session that follows the last one in the cycle.
+func (s *SessionService) StartNextSession() error {
+	switch s.session.NextSessionType() {
+	case domain.Break:
+		return s.StartBreakSession()
+	case domain.Flow:
+		return s.StartFlowSession()
+	default:
+		return s.StartWorkSession()
+	}
+}
+
 func (s *SessionService) PauseSession() error {
 	err := s.session.PauseSession()
 	if err != nil {
@@ -60,16 +109,106 @@ func (s *SessionService) ResumeSession() error {
 	return nil
 }
 
+// AbandonSession stops the running session without completing it.
+func (s *SessionService) AbandonSession() error {
+	return s.session.AbandonSession()
+}
+
+// ExtendSession adds d to the running session.
+func (s *SessionService) ExtendSession(d time.Duration) error {
+	err := s.session.ExtendSession(d)
+	if err != nil {
+		return err
+	}
+	
+	s.triggerEvent(domain.EventSessionExtended)
+	return nil
+}
+
+// ContinueOvertime keeps working after the work timer has run out.
+func (s *SessionService) ContinueOvertime() error {
+	return s.session.ContinueOvertime()
+}
+
+// StopOvertime ends overtime and returns to the end-of-work overlay.
+func (s *SessionService) StopOvertime() error {
+	return s.session.StopOvertime()
+}
+
+// CancelAutoStart stops a pending auto-start countdown.
+func (s *SessionService) CancelAutoStart() {
+	if !s.session.IsAutoStartPending() {
+		return
+	}
+	
+	s.session.CancelAutoStart()
+	s.autoStartAnnounced = false
+	s.triggerEvent(domain.EventAutoStartCancel)
+}
+
 func (s *SessionService) Update() {
 	shouldShowWarning := s.session.ShouldShowWarning()
 	
 	s.session.Update()
 	
-	// 待機状態で警告タイマーが終了したら、強制的に警告を発動
+	// 待機状態で警告タイマーが終了したら、警告ラダーの現在の段階を発動
 	if shouldShowWarning && s.session.GetState() == domain.Idle {
+		step := s.session.CurrentWarningStep()
+		s.triggerEvent(step.Severity.EventName())
 		s.triggerEvent(domain.EventWarning)
 		s.session.ResetWarningTimer()
 	}
+	
+	// カウントダウンが終わったら次のセッションを自動で開始
+	if s.session.ShouldAutoStart() {
+		s.autoStartAnnounced = false
+		s.triggerEvent(domain.EventAutoStart)
+		s.StartNextSession()
+	}
+	
+	// 一時停止の予算を使い切ったら自動で再開または中断する
+	if s.session.IsPauseBudgetExhausted() {
+		s.triggerEvent(domain.EventPauseExhausted)
+		if s.session.GetPausePolicy().OnExhausted == domain.PauseAbandon {
+			s.AbandonSession()
+		} else {
+			s.ResumeSession()
+		}
+	} else if s.session.ShouldRemindPause() {
+		s.triggerEvent(domain.EventPauseReminder)
+		s.session.ResetPauseReminder()
+	}
+}
+
+// Configure applies the session-related parts of the configuration. The
+// settings are checked before any of them is applied, so a rejected config
+// leaves the session as it was.
+func (s *SessionService) Configure(config *Config) error {
+	if err := config.validateSession(); err != nil {
+		return err
+	}
+	if err := s.session.SetDurations(config.WorkDuration, config.BreakDuration); err != nil {
+		return err
+	}
+	if err := s.session.SetWarningLadder(config.WarningLadder, config.WarningInterval); err != nil {
+		return err
+	}
+	if err := s.session.SetPausePolicy(config.Pause); err != nil {
+		return err
+	}
+	if err := s.session.SetSkipPolicy(config.Skip); err != nil {
+		return err
+	}
+	if err := s.session.SetAutoStartPolicy(config.AutoStart); err != nil {
+		return err
+	}
+	if err := s.session.SetOvertimePolicy(config.Overtime); err != nil {
+		return err
+	}
+	if err := s.session.SetExtendPolicy(config.Extend); err != nil {
+		return err
+	}
+	return s.session.SetFlowtimePolicy(config.Flowtime)
 }
 
 func (s *SessionService) GetSession() *domain.Session {
@@ -83,29 +222,65 @@ func (s *SessionService) AddEventCallback(eventName string, callback func()) {
 	s.eventCallbacks[eventName] = append(s.eventCallbacks[eventName], callback)
 }
 
+// AddEventListener registers a listener that is called with the name of every
+// event, after the callbacks registered for that event.
+func (s *SessionService) AddEventListener(listener func(eventName string)) {
+	s.eventListeners = append(s.eventListeners, listener)
+}
+
 func (s *SessionService) triggerEvent(eventName string) {
 	if callbacks, exists := s.eventCallbacks[eventName]; exists {
 		for _, callback := range callbacks {
 			callback()
 		}
 	}
+	for _, listener := range s.eventListeners {
+		listener(eventName)
+	}
 }
 
 func (s *SessionService) onStateChange(oldState, newState domain.SessionState) {
+	// 別のセッションを始めるとカウントダウンは黙って消えるので、取り消しを知らせる
+	if s.autoStartAnnounced && newState != domain.Idle {
+		s.autoStartAnnounced = false
+		s.triggerEvent(domain.EventAutoStartCancel)
+	}
+	
 	switch newState {
 	case domain.WorkSession:
 		if oldState == domain.Idle {
 			s.triggerEvent(domain.EventWorkSessionStart)
 		}
 	case domain.BreakSession:
-		if oldState == domain.Idle {
+		if oldState == domain.Overtime {
+			s.triggerEvent(domain.EventOvertimeEnd)
+		}
+		if oldState == domain.Idle || oldState == domain.Overtime {
 			s.triggerEvent(domain.EventBreakSessionStart)
 		}
+	case domain.Overtime:
+		s.triggerEvent(domain.EventOvertimeStart)
+	case domain.FlowSession:
+		s.triggerEvent(domain.EventFlowSessionStart)
 	case domain.Idle:
-		if oldState == domain.WorkSession {
+		if oldState == domain.Overtime {
+			s.triggerEvent(domain.EventOvertimeEnd)
+			if s.session.WasOvertimeCapped() {
+				s.triggerEvent(domain.EventOvertimeCapped)
+			}
+		} else if s.session.WasAbandoned() {
+			s.triggerEvent(domain.EventSessionAbandon)
+		} else if oldState == domain.WorkSession {
 			s.triggerEvent(domain.EventWorkSessionEnd)
 		} else if oldState == domain.BreakSession {
 			s.triggerEvent(domain.EventBreakSessionEnd)
+		} else if oldState == domain.FlowSession {
+			s.triggerEvent(domain.EventFlowSessionEnd)
+		}
+		
+		if s.session.IsAutoStartPending() {
+			s.autoStartAnnounced = true
+			s.triggerEvent(domain.EventAutoStartSchedule)
 		}
 	}
 }
\ No newline at end of file
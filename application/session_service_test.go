@@ -2,6 +2,7 @@ package application
 
 import (
 	"testing"
+	"time"
 	
 	"karedoro/domain"
 )
@@ -206,4 +207,340 @@ func TestSessionService_Update(t *testing.T) {
 	if !session.IsSessionActive() {
 		t.Error("Session should still be active after update")
 	}
-}
\ No newline at end of file
+}
+
+func TestSessionService_Configure(t *testing.T) {
+	service := NewSessionService()
+	
+	config := DefaultConfig()
+	config.WarningLadder = []domain.WarningStep{
+		{After: time.Minute, Severity: domain.WarningNotice},
+	}
+	if err := service.Configure(config); err != nil {
+		t.Errorf("Configure should not return error, got %v", err)
+	}
+	
+	ladder := service.GetSession().GetWarningLadder()
+	if len(ladder) != 1 || ladder[0].Severity != domain.WarningNotice {
+		t.Errorf("Expected configured ladder, got %+v", ladder)
+	}
+	
+	config.WorkDuration = 10 * time.Minute
+	config.WarningLadder = nil
+	if err := service.Configure(config); err == nil {
+		t.Error("Configure should reject an empty warning ladder")
+	}
+	if got := service.GetSession().GetDuration(domain.Work); got == 10*time.Minute {
+		t.Error("A rejected config should not change the work duration")
+	}
+}
+
+func TestSessionService_PauseBudgetAutoResume(t *testing.T) {
+	service := NewSessionService()
+	service.GetSession().SetPausePolicy(domain.PausePolicy{MaxPauseTime: 20 * time.Millisecond})
+	
+	var exhaustedCalled, resumeCalled bool
+	service.AddEventCallback(domain.EventPauseExhausted, func() {
+		exhaustedCalled = true
+	})
+	service.AddEventCallback(domain.EventSessionResume, func() {
+		resumeCalled = true
+	})
+	
+	service.StartWorkSession()
+	service.PauseSession()
+	time.Sleep(30 * time.Millisecond)
+	service.Update()
+	
+	if !exhaustedCalled || !resumeCalled {
+		t.Error("Exhausted pause budget should trigger an automatic resume")
+	}
+	if service.GetSession().IsSessionPaused() {
+		t.Error("Session should have been resumed")
+	}
+}
+
+func TestSessionService_PauseBudgetAbandon(t *testing.T) {
+	service := NewSessionService()
+	service.GetSession().SetPausePolicy(domain.PausePolicy{
+		MaxPauseTime: 20 * time.Millisecond,
+		OnExhausted:  domain.PauseAbandon,
+	})
+	
+	var abandonCalled, endCalled bool
+	service.AddEventCallback(domain.EventSessionAbandon, func() {
+		abandonCalled = true
+	})
+	service.AddEventCallback(domain.EventWorkSessionEnd, func() {
+		endCalled = true
+	})
+	
+	service.StartWorkSession()
+	service.PauseSession()
+	time.Sleep(30 * time.Millisecond)
+	service.Update()
+	
+	if !abandonCalled {
+		t.Error("Exhausted pause budget should abandon the session")
+	}
+	if endCalled {
+		t.Error("An abandoned session should not fire the end event")
+	}
+	if service.GetSession().GetState() != domain.Idle {
+		t.Errorf("Expected Idle after abandon, got %v", service.GetSession().GetState())
+	}
+}
+
+func TestSessionService_StrictModePause(t *testing.T) {
+	service := NewSessionService()
+	service.GetSession().SetPausePolicy(domain.PausePolicy{Strict: true})
+	
+	var pauseCalled bool
+	service.AddEventCallback(domain.EventSessionPause, func() {
+		pauseCalled = true
+	})
+	
+	service.StartWorkSession()
+	if err := service.PauseSession(); err == nil {
+		t.Error("PauseSession should fail in strict mode")
+	}
+	if pauseCalled {
+		t.Error("Pause event should not fire when pausing is refused")
+	}
+}
+
+func TestSessionService_CancelAutoStartWithoutPending(t *testing.T) {
+	service := NewSessionService()
+	
+	var cancelCalled bool
+	service.AddEventCallback(domain.EventAutoStartCancel, func() {
+		cancelCalled = true
+	})
+	
+	service.CancelAutoStart()
+	if cancelCalled {
+		t.Error("Cancel event should not fire when nothing is pending")
+	}
+}
+
+func TestSessionService_StartingAnotherSessionCancelsAutoStart(t *testing.T) {
+	starts := map[string]func(*SessionService) error{
+		"work":     (*SessionService).StartWorkSession,
+		"skip":     (*SessionService).SkipBreak,
+		"flow":     (*SessionService).StartFlowSession,
+		"overtime": (*SessionService).ContinueOvertime,
+	}
+	for name, start := range starts {
+		service := NewSessionService()
+		config := DefaultConfig()
+		config.WorkDuration = 20 * time.Millisecond
+		config.AutoStart = domain.AutoStartPolicy{Enabled: true, Delay: time.Minute}
+		if err := service.Configure(config); err != nil {
+			t.Fatalf("Configure should not return error, got %v", err)
+		}
+		
+		var events []string
+		service.AddEventListener(func(event string) {
+			events = append(events, event)
+		})
+		
+		service.StartWorkSession()
+		time.Sleep(30 * time.Millisecond)
+		service.Update()
+		if !service.GetSession().IsAutoStartPending() {
+			t.Fatalf("%s: auto-start should be pending after the work session ends", name)
+		}
+		
+		events = nil
+		if err := start(service); err != nil {
+			t.Fatalf("%s: should start, got %v", name, err)
+		}
+		if len(events) == 0 || events[0] != domain.EventAutoStartCancel {
+			t.Errorf("%s: expected %s before the start, got %v", name, domain.EventAutoStartCancel, events)
+		}
+		
+		// 取り消しは一度だけ
+		events = nil
+		service.AbandonSession()
+		service.StopOvertime()
+		for _, event := range events {
+			if event == domain.EventAutoStartCancel {
+				t.Errorf("%s: cancel fired again after %v", name, events)
+			}
+		}
+	}
+}
+
+func TestSessionService_ConfigureAutoStart(t *testing.T) {
+	service := NewSessionService()
+	
+	config := DefaultConfig()
+	config.AutoStart = domain.AutoStartPolicy{Enabled: true}
+	if err := service.Configure(config); err == nil {
+		t.Error("Configure should reject an enabled auto-start without a delay")
+	}
+	
+	config.AutoStart.Delay = 10 * time.Second
+	if err := service.Configure(config); err != nil {
+		t.Errorf("Configure should accept a valid auto-start policy, got %v", err)
+	}
+	if !service.GetSession().GetAutoStartPolicy().Enabled {
+		t.Error("Auto-start should be enabled after Configure")
+	}
+}
+
+func TestSessionService_AutoStartCycle(t *testing.T) {
+	service := NewSessionService()
+	
+	config := DefaultConfig()
+	config.WorkDuration = 20 * time.Millisecond
+	config.AutoStart = domain.AutoStartPolicy{Enabled: true, Delay: 20 * time.Millisecond}
+	if err := service.Configure(config); err != nil {
+		t.Fatalf("Configure should not return error, got %v", err)
+	}
+	
+	var events []string
+	for _, event := range []string{domain.EventAutoStartSchedule, domain.EventAutoStart, domain.EventBreakSessionStart} {
+		event := event
+		service.AddEventCallback(event, func() {
+			events = append(events, event)
+		})
+	}
+	
+	service.StartWorkSession()
+	time.Sleep(30 * time.Millisecond)
+	service.Update()
+	
+	if !service.GetSession().IsAutoStartPending() {
+		t.Fatal("Auto-start should be pending after the work session ends")
+	}
+	
+	time.Sleep(30 * time.Millisecond)
+	service.Update()
+	
+	if service.GetSession().GetState() != domain.BreakSession {
+		t.Errorf("Expected the break to start automatically, got %v", service.GetSession().GetState())
+	}
+	expected := []string{domain.EventAutoStartSchedule, domain.EventAutoStart, domain.EventBreakSessionStart}
+	if len(events) < len(expected) {
+		t.Fatalf("Expected events %v, got %v", expected, events)
+	}
+	for i, event := range expected {
+		if events[i] != event {
+			t.Errorf("Expected event %d to be %s, got %s", i, event, events[i])
+		}
+	}
+}
+
+func TestSessionService_OvertimeEvents(t *testing.T) {
+	service := NewSessionService()
+	
+	config := DefaultConfig()
+	config.WorkDuration = 10 * time.Millisecond
+	service.Configure(config)
+	
+	var started, ended bool
+	service.AddEventCallback(domain.EventOvertimeStart, func() {
+		started = true
+	})
+	service.AddEventCallback(domain.EventOvertimeEnd, func() {
+		ended = true
+	})
+	
+	service.StartWorkSession()
+	time.Sleep(20 * time.Millisecond)
+	service.Update()
+	
+	if err := service.ContinueOvertime(); err != nil {
+		t.Fatalf("ContinueOvertime should not return error, got %v", err)
+	}
+	if !started {
+		t.Error("overtime_start should fire")
+	}
+	
+	if err := service.StopOvertime(); err != nil {
+		t.Errorf("StopOvertime should not return error, got %v", err)
+	}
+	if !ended {
+		t.Error("overtime_end should fire")
+	}
+	if !service.GetSession().IsBreakDue() {
+		t.Error("Break should still be due after stopping overtime")
+	}
+}
+
+func TestSessionService_FlowSessionEvents(t *testing.T) {
+	service := NewSessionService()
+	
+	var started, ended bool
+	service.AddEventCallback(domain.EventFlowSessionStart, func() {
+		started = true
+	})
+	service.AddEventCallback(domain.EventFlowSessionEnd, func() {
+		ended = true
+	})
+	
+	if err := service.StartFlowSession(); err != nil {
+		t.Fatalf("StartFlowSession should not return error, got %v", err)
+	}
+	if !started {
+		t.Error("flow_session_start should fire")
+	}
+	
+	if err := service.StopFlowSession(); err != nil {
+		t.Fatalf("StopFlowSession should not return error, got %v", err)
+	}
+	if !ended {
+		t.Error("flow_session_end should fire")
+	}
+	if !service.GetSession().IsBreakDue() {
+		t.Error("Break should be due after a flow session")
+	}
+}
+
+func TestSessionService_Status(t *testing.T) {
+	service := NewSessionService()
+	
+	status := service.Status()
+	if status.State != "Idle" || status.Remaining != 0 {
+		t.Errorf("Expected idle status, got %+v", status)
+	}
+	
+	service.StartWorkSession()
+	status = service.Status()
+	if status.State != "WorkSession" || status.SessionType != "Work" {
+		t.Errorf("Expected a work session, got %+v", status)
+	}
+	if status.Remaining != int(domain.WorkSessionDuration/time.Second) {
+		t.Errorf("Expected %v remaining, got %ds", domain.WorkSessionDuration, status.Remaining)
+	}
+	if !status.CanPause {
+		t.Error("A fresh work session should be pausable")
+	}
+	
+	service.PauseSession()
+	if !service.Status().Paused {
+		t.Error("Status should report the pause")
+	}
+}
+
+func TestSessionService_StartEventsFireOnce(t *testing.T) {
+	service := NewSessionService()
+	
+	counts := make(map[string]int)
+	service.AddEventListener(func(event string) {
+		counts[event]++
+	})
+	
+	service.StartWorkSession()
+	service.StartWorkSession()
+	service.AbandonSession()
+	service.StartBreakSession()
+	
+	if counts[domain.EventWorkSessionStart] != 1 {
+		t.Errorf("Expected one work start event, got %d", counts[domain.EventWorkSessionStart])
+	}
+	if counts[domain.EventBreakSessionStart] != 1 {
+		t.Errorf("Expected one break start event, got %d", counts[domain.EventBreakSessionStart])
+	}
+}
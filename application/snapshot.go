@@ -0,0 +1,113 @@
+package application
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"karedoro/domain"
+)
+
+// Snapshot is the state file a running instance publishes so that status
+// bars can poll it without talking to the instance. It is rewritten on every
+// session event; readers extrapolate the countdown with At.
+type Snapshot struct {
+	Status
+	PID       int       `json:"pid"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// At returns the status as it will be at now, assuming no event has
+// happened since the snapshot was written.
+func (s Snapshot) At(now time.Time) Status {
+	status := s.Status
+	delta := int(now.Sub(s.UpdatedAt) / time.Second)
+	if delta <= 0 {
+		return status
+	}
+	
+	if status.AutoStartIn > 0 {
+		status.AutoStartIn = max(status.AutoStartIn-delta, 0)
+	}
+	if status.Paused {
+		return status
+	}
+	
+	switch status.State {
+	case domain.WorkSession.String(), domain.BreakSession.String(), domain.Overtime.String():
+		total := status.Elapsed + status.Remaining
+		status.Remaining = max(status.Remaining-delta, 0)
+		status.Elapsed += delta
+		if total > 0 && status.Remaining > 0 {
+			status.Progress = float64(status.Elapsed) / float64(total)
+		} else if total > 0 {
+			status.Progress = 1
+		}
+	case domain.FlowSession.String():
+		status.Elapsed += delta
+	}
+	return status
+}
+
+// ReadSnapshot reads the state file at path.
+func ReadSnapshot(path string) (Snapshot, error) {
+	var snapshot Snapshot
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return snapshot, err
+	}
+	err = json.Unmarshal(data, &snapshot)
+	return snapshot, err
+}
+
+// SnapshotWriter keeps a state file in sync with a session.
+type SnapshotWriter struct {
+	path string
+}
+
+func NewSnapshotWriter(path string) *SnapshotWriter {
+	return &SnapshotWriter{path: path}
+}
+
+// Attach writes the current state and rewrites it after every event.
+func (w *SnapshotWriter) Attach(sessionService *SessionService) {
+	w.write(sessionService.Status())
+	sessionService.AddEventListener(func(string) {
+		w.write(sessionService.Status())
+	})
+}
+
+func (w *SnapshotWriter) write(status Status) {
+	if err := w.Write(status, time.Now()); err != nil {
+		log.Printf("Warning: failed to write state snapshot: %v", err)
+	}
+}
+
+// Write replaces the state file atomically, so readers never see a partial
+// snapshot.
+func (w *SnapshotWriter) Write(status Status, now time.Time) error {
+	data, err := json.Marshal(Snapshot{
+		Status:    status,
+		PID:       os.Getpid(),
+		UpdatedAt: now,
+	})
+	if err != nil {
+		return err
+	}
+	
+	if err := os.MkdirAll(filepath.Dir(w.path), 0700); err != nil {
+		return err
+	}
+	tmp := w.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, w.path)
+}
+
+// Remove deletes the state file when the instance shuts down.
+func (w *SnapshotWriter) Remove() error {
+	return os.Remove(w.path)
+}
@@ -0,0 +1,66 @@
+package application
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"karedoro/domain"
+)
+
+func TestSnapshot_At(t *testing.T) {
+	now := time.Now()
+	snapshot := Snapshot{
+		Status: Status{
+			State:     domain.WorkSession.String(),
+			Remaining: 60,
+			Elapsed:   60,
+		},
+		UpdatedAt: now,
+	}
+	
+	status := snapshot.At(now.Add(30 * time.Second))
+	if status.Remaining != 30 || status.Elapsed != 90 {
+		t.Errorf("Expected 30s remaining and 90s elapsed, got %d and %d", status.Remaining, status.Elapsed)
+	}
+	if status.Progress != 0.75 {
+		t.Errorf("Expected progress 0.75, got %v", status.Progress)
+	}
+	
+	status = snapshot.At(now.Add(5 * time.Minute))
+	if status.Remaining != 0 || status.Progress != 1 {
+		t.Errorf("Countdown should stop at zero, got %d remaining", status.Remaining)
+	}
+	
+	snapshot.Paused = true
+	if status := snapshot.At(now.Add(30 * time.Second)); status.Remaining != 60 {
+		t.Errorf("Paused countdown should not move, got %d remaining", status.Remaining)
+	}
+}
+
+func TestSnapshotWriter_Attach(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	service := NewSessionService()
+	writer := NewSnapshotWriter(path)
+	writer.Attach(service)
+	
+	snapshot, err := ReadSnapshot(path)
+	if err != nil {
+		t.Fatalf("ReadSnapshot should not return error, got %v", err)
+	}
+	if snapshot.State != domain.Idle.String() || snapshot.PID != os.Getpid() {
+		t.Errorf("Expected Idle snapshot from this process, got %+v", snapshot)
+	}
+	
+	service.StartWorkSession()
+	snapshot, _ = ReadSnapshot(path)
+	if snapshot.State != domain.WorkSession.String() {
+		t.Errorf("Snapshot should follow session events, got %v", snapshot.State)
+	}
+	
+	writer.Remove()
+	if _, err := ReadSnapshot(path); !os.IsNotExist(err) {
+		t.Errorf("Remove should delete the snapshot, got %v", err)
+	}
+}
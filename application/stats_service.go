@@ -0,0 +1,176 @@
+package application
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"karedoro/domain"
+)
+
+// DailyStats is the record of one calendar day.
+type DailyStats struct {
+	Date                   string `json:"date"`
+	WorkSessionsCompleted  int    `json:"work_sessions_completed"`
+	BreakSessionsCompleted int    `json:"break_sessions_completed"`
+	SessionsAbandoned      int    `json:"sessions_abandoned"`
+	BreaksSkipped          int    `json:"breaks_skipped"`
+	Pauses                 int    `json:"pauses"`
+	
+	// Overtime is tracked separately from completed work sessions.
+	OvertimeSessions int           `json:"overtime_sessions"`
+	Overtime         time.Duration `json:"overtime"`
+	
+	// Flowtime sessions and the time spent in them.
+	FlowSessionsCompleted int           `json:"flow_sessions_completed"`
+	FlowTime              time.Duration `json:"flow_time"`
+	
+	// Extensions of running sessions and the time they added.
+	Extensions   int           `json:"extensions"`
+	ExtendedTime time.Duration `json:"extended_time"`
+}
+
+// StatsService keeps per-day session history in ~/.karedoro/stats.json.
+type StatsService struct {
+	days      map[string]*DailyStats
+	statsPath string
+}
+
+func NewStatsService() *StatsService {
+	homeDir, _ := os.UserHomeDir()
+	statsPath := filepath.Join(homeDir, ".karedoro", "stats.json")
+	
+	service := &StatsService{
+		days:      make(map[string]*DailyStats),
+		statsPath: statsPath,
+	}
+	
+	service.Load()
+	return service
+}
+
+func (st *StatsService) Load() error {
+	data, err := os.ReadFile(st.statsPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	
+	var days []*DailyStats
+	if err := json.Unmarshal(data, &days); err != nil {
+		return err
+	}
+	
+	for _, day := range days {
+		st.days[day.Date] = day
+	}
+	return nil
+}
+
+func (st *StatsService) Save() error {
+	dir := filepath.Dir(st.statsPath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	
+	data, err := json.MarshalIndent(st.History(), "", "  ")
+	if err != nil {
+		return err
+	}
+	
+	return os.WriteFile(st.statsPath, data, 0644)
+}
+
+// Today returns a copy of today's record.
+func (st *StatsService) Today() DailyStats {
+	return st.Day(time.Now())
+}
+
+// Day returns a copy of the record for the given day.
+func (st *StatsService) Day(t time.Time) DailyStats {
+	date := t.Format("2006-01-02")
+	if day, exists := st.days[date]; exists {
+		return *day
+	}
+	return DailyStats{Date: date}
+}
+
+// History returns every recorded day, oldest first.
+func (st *StatsService) History() []*DailyStats {
+	days := make([]*DailyStats, 0, len(st.days))
+	for _, day := range st.days {
+		days = append(days, day)
+	}
+	sort.Slice(days, func(i, j int) bool {
+		return days[i].Date < days[j].Date
+	})
+	return days
+}
+
+// Record applies update to today's record and persists the history.
+func (st *StatsService) Record(update func(*DailyStats)) error {
+	date := time.Now().Format("2006-01-02")
+	day, exists := st.days[date]
+	if !exists {
+		day = &DailyStats{Date: date}
+		st.days[date] = day
+	}
+	
+	update(day)
+	return st.Save()
+}
+
+// Attach records session events as they happen and seeds the session's skip
+// and extension allowances with what has already been used today.
+func (st *StatsService) Attach(sessionService *SessionService) {
+	sessionService.GetSession().RestoreSkips(time.Now(), st.Today().BreaksSkipped)
+	sessionService.GetSession().RestoreExtensions(time.Now(), st.Today().Extensions)
+	
+	sessionService.AddEventCallback(domain.EventWorkSessionEnd, func() {
+		st.Record(func(day *DailyStats) { day.WorkSessionsCompleted++ })
+	})
+	
+	sessionService.AddEventCallback(domain.EventBreakSessionEnd, func() {
+		st.Record(func(day *DailyStats) { day.BreakSessionsCompleted++ })
+	})
+	
+	sessionService.AddEventCallback(domain.EventSessionAbandon, func() {
+		st.Record(func(day *DailyStats) { day.SessionsAbandoned++ })
+	})
+	
+	sessionService.AddEventCallback(domain.EventBreakSkipped, func() {
+		st.Record(func(day *DailyStats) { day.BreaksSkipped++ })
+	})
+	
+	sessionService.AddEventCallback(domain.EventFlowSessionEnd, func() {
+		worked := sessionService.GetSession().LastFlowWork()
+		st.Record(func(day *DailyStats) {
+			day.FlowSessionsCompleted++
+			day.FlowTime += worked
+		})
+	})
+	
+	sessionService.AddEventCallback(domain.EventOvertimeEnd, func() {
+		overtime := sessionService.GetSession().LastOvertime()
+		st.Record(func(day *DailyStats) {
+			day.OvertimeSessions++
+			day.Overtime += overtime
+		})
+	})
+	
+	sessionService.AddEventCallback(domain.EventSessionExtended, func() {
+		extension := sessionService.GetSession().LastExtension()
+		st.Record(func(day *DailyStats) {
+			day.Extensions++
+			day.ExtendedTime += extension
+		})
+	})
+	
+	sessionService.AddEventCallback(domain.EventSessionPause, func() {
+		st.Record(func(day *DailyStats) { day.Pauses++ })
+	})
+}
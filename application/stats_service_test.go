@@ -0,0 +1,105 @@
+package application
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+	
+	"karedoro/domain"
+)
+
+func TestStatsService_RecordAndLoad(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "karedoro_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", tempDir)
+	defer os.Setenv("HOME", originalHome)
+	
+	service := NewStatsService()
+	if err := service.Record(func(day *DailyStats) { day.BreaksSkipped += 2 }); err != nil {
+		t.Errorf("Record should not return error, got %v", err)
+	}
+	
+	if _, err := os.Stat(filepath.Join(tempDir, ".karedoro", "stats.json")); err != nil {
+		t.Errorf("Stats file should have been created: %v", err)
+	}
+	
+	loaded := NewStatsService()
+	if loaded.Today().BreaksSkipped != 2 {
+		t.Errorf("Expected 2 skipped breaks after reload, got %d", loaded.Today().BreaksSkipped)
+	}
+}
+
+func TestStatsService_AttachRecordsEvents(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "karedoro_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", tempDir)
+	defer os.Setenv("HOME", originalHome)
+	
+	stats := NewStatsService()
+	stats.Record(func(day *DailyStats) { day.BreaksSkipped = 1 })
+	
+	sessionService := NewSessionService()
+	sessionService.GetSession().SetSkipPolicy(domain.SkipPolicy{DailyAllowance: 1})
+	stats.Attach(sessionService)
+	
+	if sessionService.GetSession().SkipsRemaining() != 0 {
+		t.Errorf("Attach should restore today's skips, got %d remaining", sessionService.GetSession().SkipsRemaining())
+	}
+	
+	sessionService.StartWorkSession()
+	sessionService.PauseSession()
+	sessionService.AbandonSession()
+	
+	today := stats.Today()
+	if today.Pauses != 1 {
+		t.Errorf("Expected 1 pause recorded, got %d", today.Pauses)
+	}
+	if today.SessionsAbandoned != 1 {
+		t.Errorf("Expected 1 abandoned session recorded, got %d", today.SessionsAbandoned)
+	}
+}
+
+func TestStatsService_RecordsExtensions(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "karedoro_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", tempDir)
+	defer os.Setenv("HOME", originalHome)
+	
+	stats := NewStatsService()
+	sessionService := NewSessionService()
+	stats.Attach(sessionService)
+	
+	var extended bool
+	sessionService.AddEventCallback(domain.EventSessionExtended, func() {
+		extended = true
+	})
+	
+	sessionService.StartWorkSession()
+	if err := sessionService.ExtendSession(2 * time.Minute); err != nil {
+		t.Fatalf("ExtendSession should not return error, got %v", err)
+	}
+	if !extended {
+		t.Error("session_extended should fire")
+	}
+	
+	today := stats.Today()
+	if today.Extensions != 1 || today.ExtendedTime != 2*time.Minute {
+		t.Errorf("Expected 1 extension of 2m recorded, got %d/%v", today.Extensions, today.ExtendedTime)
+	}
+}
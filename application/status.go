@@ -0,0 +1,60 @@
+package application
+
+import (
+	"time"
+
+	"karedoro/domain"
+)
+
+// Status is a point-in-time snapshot of the session for clients outside the
+// UI. Durations are whole seconds so that shell scripts can use them directly.
+type Status struct {
+	State       string  `json:"state"`
+	SessionType string  `json:"session_type"`
+	Paused      bool    `json:"paused"`
+	Remaining   int     `json:"remaining"`
+	Elapsed     int     `json:"elapsed"`
+	Progress    float64 `json:"progress"`
+	BreakDue    bool    `json:"break_due"`
+	AutoStartIn int     `json:"auto_start_in,omitempty"`
+	CanPause    bool    `json:"can_pause"`
+	CanExtend   bool    `json:"can_extend"`
+	CanSkip     bool    `json:"can_skip"`
+}
+
+// Status returns a snapshot of the current session.
+func (s *SessionService) Status() Status {
+	session := s.session
+	status := Status{
+		State:       session.GetState().String(),
+		SessionType: session.GetSessionType().String(),
+		Paused:      session.IsSessionPaused(),
+		BreakDue:    session.IsBreakDue(),
+		CanPause:    session.CanPause(),
+		CanExtend:   session.CanExtend(),
+		CanSkip:     session.CanSkipBreak(),
+	}
+	
+	switch session.GetState() {
+	case domain.WorkSession, domain.BreakSession:
+		status.Remaining = seconds(session.GetTimeRemaining())
+		status.Elapsed = seconds(session.GetCurrentTimer().Elapsed())
+		status.Progress = session.GetProgress()
+	case domain.FlowSession:
+		status.Elapsed = seconds(session.FlowElapsed())
+	case domain.Overtime:
+		status.Remaining = seconds(session.OvertimeRemaining())
+		status.Elapsed = seconds(session.OvertimeElapsed())
+		status.Progress = session.GetOvertimeProgress()
+	case domain.Idle:
+		if session.IsAutoStartPending() {
+			status.AutoStartIn = seconds(session.AutoStartRemaining())
+		}
+	}
+	return status
+}
+
+// seconds rounds up so that a countdown shows 1 until it has fully run out.
+func seconds(d time.Duration) int {
+	return int((d + time.Second - 1) / time.Second)
+}
@@ -0,0 +1,74 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+var errNotTerminal = errors.New("terminal sink: output is not a terminal")
+
+// TerminalSink rings the terminal's bell and, where the terminal is known to
+// show them, raises a desktop notification with an OSC 9 or OSC 777 escape.
+type TerminalSink struct {
+	out io.Writer
+	osc string
+}
+
+// NewTerminalSink writes to out, choosing the escape from the environment.
+func NewTerminalSink(out io.Writer) *TerminalSink {
+	return &TerminalSink{out: out, osc: terminalOSC(os.Getenv)}
+}
+
+func (t *TerminalSink) Notify(notification Notification) error {
+	// 端末でなければ次のシンクに任せる
+	if file, ok := t.out.(*os.File); ok {
+		info, err := file.Stat()
+		if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+			return errNotTerminal
+		}
+	}
+	
+	title := sanitizeEscape(notification.Title)
+	body := sanitizeEscape(notification.Body)
+	
+	var err error
+	switch t.osc {
+	case "9":
+		_, err = fmt.Fprintf(t.out, "\a\x1b]9;%s: %s\a", title, body)
+	case "777":
+		_, err = fmt.Fprintf(t.out, "\a\x1b]777;notify;%s;%s\a", strings.ReplaceAll(title, ";", ","), body)
+	default:
+		_, err = fmt.Fprint(t.out, "\a")
+	}
+	return err
+}
+
+// terminalOSC returns the notification escape understood by the terminal
+// described by getenv, or "" to only ring the bell.
+func terminalOSC(getenv func(string) string) string {
+	switch getenv("TERM_PROGRAM") {
+	case "iTerm.app", "WezTerm", "ghostty":
+		return "9"
+	}
+	term := getenv("TERM")
+	switch {
+	case getenv("WT_SESSION") != "", term == "xterm-kitty":
+		return "9"
+	case strings.Contains(term, "rxvt"), strings.HasPrefix(term, "foot"), getenv("VTE_VERSION") != "":
+		return "777"
+	}
+	return ""
+}
+
+// sanitizeEscape drops control characters, which would end the escape early.
+func sanitizeEscape(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f {
+			return -1
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,77 @@
+package application
+
+import (
+	"fmt"
+	"time"
+
+	"karedoro/theme"
+)
+
+// clockLayout is how times of day are written in the theme settings.
+const clockLayout = "15:04"
+
+// ThemeConfig chooses the window colours. Name is a built-in theme or a
+// file in the themes directory of the config directory. With AutoDark the
+// Dark theme is used from DarkFrom until LightFrom, such as "19:00" to
+// "07:00".
+type ThemeConfig struct {
+	Name      string `json:"name"`
+	AutoDark  bool   `json:"auto_dark"`
+	Dark      string `json:"dark"`
+	DarkFrom  string `json:"dark_from"`
+	LightFrom string `json:"light_from"`
+}
+
+// DefaultThemeConfig uses the dark theme all day.
+func DefaultThemeConfig() ThemeConfig {
+	return ThemeConfig{
+		Name:      theme.DefaultName,
+		Dark:      theme.DefaultName,
+		DarkFrom:  "19:00",
+		LightFrom: "07:00",
+	}
+}
+
+// Validate reports whether the times of day can be read.
+func (c ThemeConfig) Validate() error {
+	if !c.AutoDark {
+		return nil
+	}
+	for _, clock := range []string{c.DarkFrom, c.LightFrom} {
+		if _, err := time.Parse(clockLayout, clock); err != nil {
+			return fmt.Errorf("invalid theme time %q (want HH:MM)", clock)
+		}
+	}
+	return nil
+}
+
+// Active returns the name of the theme to use at now.
+func (c ThemeConfig) Active(now time.Time) string {
+	if !c.AutoDark || c.Dark == "" {
+		return c.Name
+	}
+	darkFrom, err := time.Parse(clockLayout, c.DarkFrom)
+	if err != nil {
+		return c.Name
+	}
+	lightFrom, err := time.Parse(clockLayout, c.LightFrom)
+	if err != nil {
+		return c.Name
+	}
+	
+	minute := now.Hour()*60 + now.Minute()
+	dark := darkFrom.Hour()*60 + darkFrom.Minute()
+	light := lightFrom.Hour()*60 + lightFrom.Minute()
+	
+	// 暗い時間帯が日付をまたぐ場合（19:00〜07:00 など）も扱う
+	var isDark bool
+	if dark <= light {
+		isDark = minute >= dark && minute < light
+	} else {
+		isDark = minute >= dark || minute < light
+	}
+	if isDark {
+		return c.Dark
+	}
+	return c.Name
+}
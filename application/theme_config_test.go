@@ -0,0 +1,61 @@
+package application
+
+import (
+	"testing"
+	"time"
+)
+
+func TestThemeConfig_Active(t *testing.T) {
+	config := ThemeConfig{Name: "light", AutoDark: true, Dark: "dark", DarkFrom: "19:00", LightFrom: "07:00"}
+	at := func(clock string) time.Time {
+		parsed, _ := time.Parse("15:04", clock)
+		return time.Date(2025, 1, 16, parsed.Hour(), parsed.Minute(), 0, 0, time.Local)
+	}
+	
+	tests := map[string]string{
+		"06:59": "dark",
+		"07:00": "light",
+		"12:00": "light",
+		"18:59": "light",
+		"19:00": "dark",
+		"23:30": "dark",
+		"00:00": "dark",
+	}
+	for clock, want := range tests {
+		if got := config.Active(at(clock)); got != want {
+			t.Errorf("At %s expected %s, got %s", clock, want, got)
+		}
+	}
+	
+	// 日付をまたがない暗い時間帯
+	config.DarkFrom, config.LightFrom = "13:00", "14:00"
+	if got := config.Active(at("13:30")); got != "dark" {
+		t.Errorf("Expected dark within the range, got %s", got)
+	}
+	if got := config.Active(at("15:00")); got != "light" {
+		t.Errorf("Expected light outside the range, got %s", got)
+	}
+	
+	config.AutoDark = false
+	if got := config.Active(at("13:30")); got != "light" {
+		t.Errorf("Expected the chosen theme without auto dark, got %s", got)
+	}
+}
+
+func TestThemeConfig_Validate(t *testing.T) {
+	config := DefaultThemeConfig()
+	config.DarkFrom = "7pm"
+	if err := config.Validate(); err != nil {
+		t.Errorf("Times should not matter without auto dark: %v", err)
+	}
+	
+	config.AutoDark = true
+	if err := config.Validate(); err == nil {
+		t.Error("Expected an invalid time to be rejected")
+	}
+	
+	config.DarkFrom = "19:00"
+	if err := config.Validate(); err != nil {
+		t.Errorf("Expected valid times to pass: %v", err)
+	}
+}
@@ -0,0 +1,231 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"text/template"
+	"time"
+)
+
+// WebhookSignatureHeader carries the hex HMAC-SHA256 of the body, keyed with
+// the webhook's secret, as "sha256=<hex>".
+const WebhookSignatureHeader = "X-Karedoro-Signature"
+
+// Webhook delivery defaults.
+const (
+	DefaultWebhookAttempts = 4
+	DefaultWebhookBackoff  = 2 * time.Second
+	DefaultWebhookTimeout  = 10 * time.Second
+	
+	// webhookQueueSize bounds the deliveries waiting per webhook; events
+	// beyond it are dropped rather than blocking the session.
+	webhookQueueSize = 64
+)
+
+// WebhookConfig is an HTTP endpoint notified of session events. Events lists
+// the event names to send; empty sends every event. Body is a text/template
+// rendered with a WebhookPayload; empty sends the payload as JSON.
+type WebhookConfig struct {
+	URL     string            `json:"url"`
+	Events  []string          `json:"events,omitempty"`
+	Body    string            `json:"body,omitempty"`
+	Secret  string            `json:"secret,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+	
+	// Attempts is how many times a delivery is tried, waiting Backoff before
+	// the first retry and doubling it after each. Zero uses the defaults.
+	Attempts int           `json:"attempts,omitempty"`
+	Backoff  time.Duration `json:"backoff,omitempty"`
+}
+
+// Validate checks the URL and the body template.
+func (w WebhookConfig) Validate() error {
+	u, err := url.Parse(w.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("webhook %q: url must be an http or https URL", w.URL)
+	}
+	if w.Attempts < 0 || w.Backoff < 0 {
+		return fmt.Errorf("webhook %q: attempts and backoff must not be negative", w.URL)
+	}
+	if _, err := w.template(); err != nil {
+		return fmt.Errorf("webhook %q: %w", w.URL, err)
+	}
+	return nil
+}
+
+func (w WebhookConfig) template() (*template.Template, error) {
+	if w.Body == "" {
+		return nil, nil
+	}
+	return template.New("body").Funcs(template.FuncMap{
+		"json": func(v interface{}) (string, error) {
+			data, err := json.Marshal(v)
+			return string(data), err
+		},
+	}).Parse(w.Body)
+}
+
+func (w WebhookConfig) wants(event string) bool {
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, e := range w.Events {
+		if e == event || e == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookPayload is what a webhook body is rendered from.
+type WebhookPayload struct {
+	Event  string    `json:"event"`
+	Time   time.Time `json:"time"`
+	Status Status    `json:"status"`
+}
+
+// Webhooks posts session events to the configured endpoints. Each endpoint
+// has its own queue and goroutine, so a slow or failing endpoint delays
+// neither the session nor the other endpoints.
+type Webhooks struct {
+	client    *http.Client
+	endpoints []*webhookEndpoint
+}
+
+type webhookEndpoint struct {
+	config   WebhookConfig
+	template *template.Template
+	queue    chan WebhookPayload
+}
+
+// NewWebhooks starts a sender for each webhook. Invalid webhooks are
+// reported and skipped.
+func NewWebhooks(configs []WebhookConfig, client *http.Client) (*Webhooks, error) {
+	if client == nil {
+		client = &http.Client{Timeout: DefaultWebhookTimeout}
+	}
+	webhooks := &Webhooks{client: client}
+	
+	var errs []error
+	for _, config := range configs {
+		if err := config.Validate(); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		tmpl, _ := config.template()
+		endpoint := &webhookEndpoint{
+			config:   config,
+			template: tmpl,
+			queue:    make(chan WebhookPayload, webhookQueueSize),
+		}
+		webhooks.endpoints = append(webhooks.endpoints, endpoint)
+		go webhooks.send(endpoint)
+	}
+	return webhooks, errors.Join(errs...)
+}
+
+// Attach queues every session event for the webhooks that want it.
+func (w *Webhooks) Attach(sessionService *SessionService) {
+	sessionService.AddEventListener(func(event string) {
+		payload := WebhookPayload{Event: event, Time: time.Now(), Status: sessionService.Status()}
+		for _, endpoint := range w.endpoints {
+			if !endpoint.config.wants(event) {
+				continue
+			}
+			select {
+			case endpoint.queue <- payload:
+			default:
+				log.Printf("webhook %s: dropped %s, too many deliveries pending", endpoint.config.URL, event)
+			}
+		}
+	})
+}
+
+func (w *Webhooks) send(endpoint *webhookEndpoint) {
+	for payload := range endpoint.queue {
+		body, err := endpoint.render(payload)
+		if err != nil {
+			log.Printf("webhook %s: %v", endpoint.config.URL, err)
+			continue
+		}
+		if err := w.deliver(endpoint.config, body); err != nil {
+			log.Printf("webhook %s: giving up on %s: %v", endpoint.config.URL, payload.Event, err)
+		}
+	}
+}
+
+func (e *webhookEndpoint) render(payload WebhookPayload) ([]byte, error) {
+	if e.template == nil {
+		return json.Marshal(payload)
+	}
+	var buf bytes.Buffer
+	err := e.template.Execute(&buf, payload)
+	return buf.Bytes(), err
+}
+
+// deliver posts body, retrying with exponential backoff on network errors,
+// 429 and 5xx responses.
+func (w *Webhooks) deliver(config WebhookConfig, body []byte) error {
+	attempts, backoff := config.Attempts, config.Backoff
+	if attempts == 0 {
+		attempts = DefaultWebhookAttempts
+	}
+	if backoff == 0 {
+		backoff = DefaultWebhookBackoff
+	}
+	
+	for attempt := 1; ; attempt++ {
+		retry, err := w.post(config, body)
+		if err == nil || !retry || attempt >= attempts {
+			return err
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+func (w *Webhooks) post(config WebhookConfig, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, config.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "karedoro")
+	for name, value := range config.Headers {
+		req.Header.Set(name, value)
+	}
+	if config.Secret != "" {
+		req.Header.Set(WebhookSignatureHeader, SignWebhook(config.Secret, body))
+	}
+	
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	resp.Body.Close()
+	
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return true, fmt.Errorf("server responded %s", resp.Status)
+	default:
+		return false, fmt.Errorf("server responded %s", resp.Status)
+	}
+}
+
+// SignWebhook returns the signature header value for body.
+func SignWebhook(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
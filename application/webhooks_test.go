@@ -0,0 +1,165 @@
+package application
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"karedoro/domain"
+)
+
+type webhookRequest struct {
+	header http.Header
+	body   []byte
+}
+
+func newWebhookServer(t *testing.T, status func(attempt int32) int) (*httptest.Server, chan webhookRequest) {
+	t.Helper()
+	requests := make(chan webhookRequest, 16)
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- webhookRequest{header: r.Header, body: body}
+		w.WriteHeader(status(atomic.AddInt32(&attempts, 1)))
+	}))
+	t.Cleanup(server.Close)
+	return server, requests
+}
+
+func receive(t *testing.T, requests chan webhookRequest) webhookRequest {
+	t.Helper()
+	select {
+	case req := <-requests:
+		return req
+	case <-time.After(2 * time.Second):
+		t.Fatal("Webhook was not delivered")
+		return webhookRequest{}
+	}
+}
+
+func TestWebhooks_DeliversSignedPayload(t *testing.T) {
+	server, requests := newWebhookServer(t, func(int32) int { return http.StatusOK })
+	
+	webhooks, err := NewWebhooks([]WebhookConfig{{
+		URL:    server.URL,
+		Events: []string{domain.EventWorkSessionStart},
+		Secret: "s3cret",
+	}}, server.Client())
+	if err != nil {
+		t.Fatalf("NewWebhooks should not return error, got %v", err)
+	}
+	service := NewSessionService()
+	webhooks.Attach(service)
+	
+	start := time.Now()
+	service.StartWorkSession()
+	service.PauseSession()
+	if time.Since(start) > 50*time.Millisecond {
+		t.Error("Webhooks should not block the session")
+	}
+	
+	req := receive(t, requests)
+	var payload WebhookPayload
+	if err := json.Unmarshal(req.body, &payload); err != nil {
+		t.Fatalf("Payload should be JSON, got %q", req.body)
+	}
+	if payload.Event != domain.EventWorkSessionStart || payload.Status.State != domain.WorkSession.String() {
+		t.Errorf("Unexpected payload %+v", payload)
+	}
+	if sig := req.header.Get(WebhookSignatureHeader); sig != SignWebhook("s3cret", req.body) {
+		t.Errorf("Expected a valid signature, got %q", sig)
+	}
+	
+	// session_pause is filtered out
+	select {
+	case req := <-requests:
+		t.Errorf("Unexpected delivery %q", req.body)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestWebhooks_BodyTemplate(t *testing.T) {
+	server, requests := newWebhookServer(t, func(int32) int { return http.StatusOK })
+	
+	webhooks, err := NewWebhooks([]WebhookConfig{{
+		URL:  server.URL,
+		Body: `{"text":"karedoro: {{.Event}} ({{.Status.Remaining}}s)","state":{{json .Status.State}}}`,
+	}}, server.Client())
+	if err != nil {
+		t.Fatalf("NewWebhooks should not return error, got %v", err)
+	}
+	service := NewSessionService()
+	webhooks.Attach(service)
+	service.StartWorkSession()
+	
+	req := receive(t, requests)
+	expected := `{"text":"karedoro: work_session_start (1500s)","state":"WorkSession"}`
+	if string(req.body) != expected {
+		t.Errorf("Expected %s, got %s", expected, req.body)
+	}
+}
+
+func TestWebhooks_RetriesWithBackoff(t *testing.T) {
+	server, requests := newWebhookServer(t, func(attempt int32) int {
+		if attempt < 3 {
+			return http.StatusServiceUnavailable
+		}
+		return http.StatusOK
+	})
+	
+	webhooks, _ := NewWebhooks([]WebhookConfig{{
+		URL:     server.URL,
+		Backoff: 10 * time.Millisecond,
+	}}, server.Client())
+	service := NewSessionService()
+	webhooks.Attach(service)
+	service.StartWorkSession()
+	
+	first := receive(t, requests)
+	receive(t, requests)
+	third := receive(t, requests)
+	if string(first.body) != string(third.body) {
+		t.Error("Retries should resend the same body")
+	}
+}
+
+func TestWebhooks_NoRetryOnClientError(t *testing.T) {
+	server, requests := newWebhookServer(t, func(int32) int { return http.StatusBadRequest })
+	
+	webhooks, _ := NewWebhooks([]WebhookConfig{{
+		URL:     server.URL,
+		Backoff: 10 * time.Millisecond,
+	}}, server.Client())
+	service := NewSessionService()
+	webhooks.Attach(service)
+	service.StartWorkSession()
+	
+	receive(t, requests)
+	select {
+	case <-requests:
+		t.Error("4xx responses should not be retried")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestWebhookConfig_Validate(t *testing.T) {
+	invalid := []WebhookConfig{
+		{URL: "ftp://example.com"},
+		{URL: "http://"},
+		{URL: "http://example.com", Body: "{{.Event"},
+		{URL: "http://example.com", Attempts: -1},
+	}
+	for _, config := range invalid {
+		if err := config.Validate(); err == nil {
+			t.Errorf("Expected %+v to be invalid", config)
+		}
+	}
+	
+	if _, err := NewWebhooks(invalid[:1], nil); err == nil {
+		t.Error("NewWebhooks should report invalid webhooks")
+	}
+}
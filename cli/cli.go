@@ -0,0 +1,198 @@
+// Package cli implements karedoro's subcommands: running the daemon and
+// controlling it from the shell.
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"karedoro/application"
+	"karedoro/daemon"
+	"karedoro/domain"
+	"karedoro/instance"
+	"karedoro/ipc"
+	"karedoro/presentation/tui"
+)
+
+const usage = `usage: karedoro [command]
+
+With no command karedoro opens its window, or brings the running one to the
+front. "karedoro --start work|break|flow" also starts a session in it.
+
+commands:
+  daemon                  run the timer without a window
+  tui                     run the timer in the terminal
+  start work|break|flow   start a session
+  pause                   pause the running session
+  resume                  resume the paused session
+  status [--format F] [--watch]
+                          show the current session; F is one of waybar,
+                          i3blocks, polybar, tmux, plain or json
+  stop                    end the running flow session or overtime
+  abandon                 stop the running session without completing it
+  pair                    show the code for pairing the web UI
+`
+
+// Run executes the subcommand in args and returns the process exit code.
+func Run(args []string) int {
+	return run(args, os.Stdout, os.Stderr)
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+	
+	switch args[0] {
+	case "daemon":
+		return runDaemon(stderr)
+	case "tui":
+		return runTUI(stdout, stderr)
+	case ipc.CommandStatus:
+		return runStatus(args[1:], stdout, stderr)
+	case ipc.CommandStart, ipc.CommandPause, ipc.CommandResume, ipc.CommandStop, ipc.CommandAbandon, ipc.CommandPair:
+		return runClient(ipc.Request{Command: args[0], Args: args[1:]}, stdout, stderr)
+	case "help", "-h", "--help":
+		fmt.Fprint(stdout, usage)
+		return 0
+	default:
+		fmt.Fprintf(stderr, "karedoro: unknown command %q\n\n%s", args[0], usage)
+		return 2
+	}
+}
+
+func runDaemon(stderr io.Writer) int {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	
+	services := application.NewServices()
+	if err := daemon.New(services).Run(ctx, ipc.SocketPath()); err != nil {
+		fmt.Fprintf(stderr, "karedoro: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func runTUI(stdout, stderr io.Writer) int {
+	inst, err := instance.Acquire(ipc.SocketPath())
+	if errors.Is(err, ipc.ErrAlreadyRunning) {
+		return handOff(nil, stdout, stderr)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "karedoro: %v\n", err)
+		return 1
+	}
+	defer inst.Close()
+	
+	services := application.NewServices()
+	handler := instance.NewHandler(services.Loop)
+	if err := inst.Listen(ipc.SocketPath(), handler.Handle); err != nil {
+		fmt.Fprintf(stderr, "karedoro: %v\n", err)
+		return 1
+	}
+	inst.Publish(services.Session)
+	services.AttachIntegrations()
+	
+	if err := tui.Run(services); err != nil {
+		fmt.Fprintf(stderr, "karedoro: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// ParseLaunch reports whether args launch karedoro itself rather than run a
+// subcommand, and returns the action the launch asks for, if any.
+func ParseLaunch(args []string) (*ipc.Request, bool) {
+	switch {
+	case len(args) == 0:
+		return nil, true
+	case len(args) == 2 && args[0] == "--start":
+		return &ipc.Request{Command: ipc.CommandStart, Args: args[1:]}, true
+	case len(args) == 1 && strings.HasPrefix(args[0], "--start="):
+		return &ipc.Request{Command: ipc.CommandStart, Args: []string{strings.TrimPrefix(args[0], "--start=")}}, true
+	}
+	return nil, false
+}
+
+// HandOff passes a launch to the instance that is already running: it
+// raises the running instance and forwards action, if any. It returns the
+// process exit code.
+func HandOff(action *ipc.Request) int {
+	return handOff(action, os.Stdout, os.Stderr)
+}
+
+// handOffTimeout bounds how long a launch waits for a running instance that
+// holds the lock but has not opened its socket yet.
+const handOffTimeout = 3 * time.Second
+
+func handOff(action *ipc.Request, stdout, stderr io.Writer) int {
+	message := "karedoro is already running"
+	if pid := ipc.LockOwner(ipc.LockPath(ipc.SocketPath())); pid > 0 {
+		message += fmt.Sprintf(" (pid %d)", pid)
+	}
+	fmt.Fprintln(stderr, message)
+	
+	deadline := time.Now().Add(handOffTimeout)
+	for {
+		_, err := ipc.Call(ipc.SocketPath(), ipc.Request{Command: ipc.CommandRaise})
+		if !errors.Is(err, ipc.ErrNotRunning) || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	
+	if action == nil {
+		return runClient(ipc.Request{Command: ipc.CommandStatus}, stdout, stderr)
+	}
+	return runClient(*action, stdout, stderr)
+}
+
+func runClient(req ipc.Request, stdout, stderr io.Writer) int {
+	resp, err := ipc.Call(ipc.SocketPath(), req)
+	if err != nil {
+		fmt.Fprintf(stderr, "karedoro: %v\n", err)
+		return 1
+	}
+	
+	if resp.PairingCode != "" {
+		fmt.Fprintln(stdout, resp.PairingCode)
+	}
+	if resp.Status != nil {
+		fmt.Fprintln(stdout, FormatStatus(*resp.Status))
+	}
+	return 0
+}
+
+// FormatStatus renders a status as a single human-readable line.
+func FormatStatus(status application.Status) string {
+	text := status.State
+	switch status.State {
+	case domain.WorkSession.String(), domain.BreakSession.String():
+		text += " " + clock(status.Remaining) + " remaining"
+	case domain.FlowSession.String(), domain.Overtime.String():
+		text += " " + clock(status.Elapsed) + " elapsed"
+	case domain.Idle.String():
+		if status.BreakDue {
+			text += " (break due)"
+		}
+		if status.AutoStartIn > 0 {
+			text += fmt.Sprintf(" (next session in %ds)", status.AutoStartIn)
+		}
+	}
+	if status.Paused {
+		text += " [paused]"
+	}
+	return text
+}
+
+func clock(seconds int) string {
+	return fmt.Sprintf("%02d:%02d", seconds/60, seconds%60)
+}
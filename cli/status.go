@@ -0,0 +1,213 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"karedoro/application"
+	"karedoro/domain"
+	"karedoro/ipc"
+)
+
+// watchInterval is how often status --watch re-reads the snapshot.
+const watchInterval = time.Second
+
+// barFormats render a status for a status bar. A nil status means karedoro
+// is not running.
+var barFormats = map[string]func(*application.Status) string{
+	"waybar":   formatWaybar,
+	"i3blocks": formatI3blocks,
+	"polybar":  formatPolybar,
+	"tmux":     formatTmux,
+	"plain":    formatPlain,
+	"json":     formatJSON,
+}
+
+// barColors are the bar colours for each class, matching the window's palette.
+var barColors = map[string]string{
+	"work":     "#dc143c",
+	"break":    "#228b22",
+	"flow":     "#1e5aa0",
+	"overtime": "#800080",
+	"paused":   "#ffa500",
+	"idle":     "#ffffff",
+	"stopped":  "#808080",
+}
+
+func runStatus(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("status", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	format := flags.String("format", "", "output format: waybar, i3blocks, polybar, tmux, plain or json")
+	watch := flags.Bool("watch", false, "keep printing the status whenever it changes")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() > 0 {
+		fmt.Fprintf(stderr, "karedoro: unexpected argument %q\n", flags.Arg(0))
+		return 2
+	}
+	
+	// フォーマット指定がなければ従来どおりソケット経由で問い合わせる
+	if *format == "" && !*watch {
+		return runClient(ipc.Request{Command: ipc.CommandStatus}, stdout, stderr)
+	}
+	if *format == "" {
+		*format = "plain"
+	}
+	formatter, ok := barFormats[*format]
+	if !ok {
+		fmt.Fprintf(stderr, "karedoro: unknown format %q\n", *format)
+		return 2
+	}
+	
+	path := ipc.SnapshotPath(ipc.SocketPath())
+	if !*watch {
+		fmt.Fprintln(stdout, formatter(readStatus(path, time.Now())))
+		return 0
+	}
+	
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	watchStatus(ctx, path, formatter, stdout)
+	return 0
+}
+
+// readStatus returns the running instance's status, or nil if none is running.
+func readStatus(path string, now time.Time) *application.Status {
+	snapshot, err := application.ReadSnapshot(path)
+	if err != nil || snapshot.PID <= 0 || !ipc.ProcessRunning(snapshot.PID) {
+		return nil
+	}
+	status := snapshot.At(now)
+	return &status
+}
+
+// watchStatus prints a line whenever the formatted status changes, for bars
+// that keep the command running (waybar without interval, i3blocks persist,
+// polybar tail).
+func watchStatus(ctx context.Context, path string, formatter func(*application.Status) string, out io.Writer) {
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+	
+	last := ""
+	for {
+		if line := formatter(readStatus(path, time.Now())); line != last {
+			fmt.Fprintln(out, line)
+			last = line
+		}
+		
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// barClass classifies a status for styling: work, break, flow, overtime,
+// paused, idle or stopped.
+func barClass(status *application.Status) string {
+	switch {
+	case status == nil:
+		return "stopped"
+	case status.Paused:
+		return "paused"
+	}
+	
+	switch status.State {
+	case domain.WorkSession.String():
+		return "work"
+	case domain.BreakSession.String():
+		return "break"
+	case domain.FlowSession.String():
+		return "flow"
+	case domain.Overtime.String():
+		return "overtime"
+	default:
+		return "idle"
+	}
+}
+
+// barText is the short text shown in a bar, e.g. "WORK 24:12".
+func barText(status *application.Status) string {
+	if status == nil {
+		return "--:--"
+	}
+	
+	var text string
+	switch status.State {
+	case domain.WorkSession.String():
+		text = "WORK " + clock(status.Remaining)
+	case domain.BreakSession.String():
+		text = "BREAK " + clock(status.Remaining)
+	case domain.FlowSession.String():
+		text = "FLOW " + clock(status.Elapsed)
+	case domain.Overtime.String():
+		text = "OVERTIME +" + clock(status.Elapsed)
+	default:
+		text = "IDLE"
+		if status.BreakDue {
+			text = "BREAK DUE"
+		}
+	}
+	if status.Paused {
+		text = "PAUSED " + text
+	}
+	return text
+}
+
+func formatPlain(status *application.Status) string {
+	if status == nil {
+		return "karedoro is not running"
+	}
+	return FormatStatus(*status)
+}
+
+func formatJSON(status *application.Status) string {
+	data, _ := json.Marshal(struct {
+		Running bool `json:"running"`
+		*application.Status
+	}{status != nil, status})
+	return string(data)
+}
+
+func formatWaybar(status *application.Status) string {
+	percentage := 0
+	if status != nil {
+		percentage = int(status.Progress * 100)
+	}
+	data, _ := json.Marshal(struct {
+		Text       string `json:"text"`
+		Tooltip    string `json:"tooltip"`
+		Class      string `json:"class"`
+		Percentage int    `json:"percentage"`
+	}{barText(status), formatPlain(status), barClass(status), percentage})
+	return string(data)
+}
+
+// formatI3blocks prints full_text, short_text and color on separate lines.
+func formatI3blocks(status *application.Status) string {
+	short := "--:--"
+	if status != nil {
+		short = clock(status.Remaining)
+		if status.State == domain.FlowSession.String() || status.State == domain.Overtime.String() {
+			short = clock(status.Elapsed)
+		}
+	}
+	return barText(status) + "\n" + short + "\n" + barColors[barClass(status)]
+}
+
+func formatPolybar(status *application.Status) string {
+	return "%{F" + barColors[barClass(status)] + "}" + barText(status) + "%{F-}"
+}
+
+func formatTmux(status *application.Status) string {
+	return "#[fg=" + barColors[barClass(status)] + "]" + barText(status) + "#[default]"
+}
@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"karedoro/application"
+	"karedoro/domain"
+)
+
+func TestBarFormats(t *testing.T) {
+	work := &application.Status{
+		State:     domain.WorkSession.String(),
+		Remaining: 1452,
+		Elapsed:   48,
+		Progress:  0.5,
+	}
+	
+	tests := []struct {
+		format   string
+		status   *application.Status
+		expected string
+	}{
+		{"waybar", work, `{"text":"WORK 24:12","tooltip":"WorkSession 24:12 remaining","class":"work","percentage":50}`},
+		{"waybar", nil, `{"text":"--:--","tooltip":"karedoro is not running","class":"stopped","percentage":0}`},
+		{"i3blocks", work, "WORK 24:12\n24:12\n#dc143c"},
+		{"polybar", work, "%{F#dc143c}WORK 24:12%{F-}"},
+		{"tmux", &application.Status{State: domain.Idle.String(), BreakDue: true}, "#[fg=#ffffff]BREAK DUE#[default]"},
+		{"plain", nil, "karedoro is not running"},
+		{"json", nil, `{"running":false}`},
+	}
+	
+	for _, tt := range tests {
+		if got := barFormats[tt.format](tt.status); got != tt.expected {
+			t.Errorf("%s: expected %q, got %q", tt.format, tt.expected, got)
+		}
+	}
+}
+
+func TestBarClass_Paused(t *testing.T) {
+	status := &application.Status{State: domain.BreakSession.String(), Paused: true, Remaining: 60}
+	if class := barClass(status); class != "paused" {
+		t.Errorf("Expected paused class, got %q", class)
+	}
+	if text := barText(status); text != "PAUSED BREAK 01:00" {
+		t.Errorf("Expected paused text, got %q", text)
+	}
+}
+
+func TestReadStatus(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	if status := readStatus(path, time.Now()); status != nil {
+		t.Errorf("Missing snapshot should mean not running, got %+v", status)
+	}
+	
+	now := time.Now()
+	writer := application.NewSnapshotWriter(path)
+	writer.Write(application.Status{State: domain.WorkSession.String(), Remaining: 100}, now)
+	
+	status := readStatus(path, now.Add(10*time.Second))
+	if status == nil || status.Remaining != 90 {
+		t.Errorf("Expected 90s remaining, got %+v", status)
+	}
+}
+
+func TestRunStatus_UnknownFormat(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"status", "--format", "xml"}, &stdout, &stderr); code != 2 {
+		t.Errorf("Expected exit code 2, got %d", code)
+	}
+	if !strings.Contains(stderr.String(), "unknown format") {
+		t.Errorf("Expected an unknown format error, got %q", stderr.String())
+	}
+}
@@ -0,0 +1,81 @@
+// Package daemon runs karedoro without a window: the session keeps running
+// with sounds and notifications and is controlled over the ipc socket.
+package daemon
+
+import (
+	"context"
+	"log"
+
+	"karedoro/application"
+	"karedoro/dbusapi"
+	"karedoro/httpapi"
+	"karedoro/instance"
+	"karedoro/ipc"
+)
+
+// Daemon owns a headless session and answers control requests for it.
+type Daemon struct {
+	services *application.Services
+	loop     *application.Loop
+	handler  *instance.Handler
+}
+
+func New(services *application.Services) *Daemon {
+	if services.Loop == nil {
+		services.Loop = application.NewLoop(services.Session, application.LoopInterval)
+	}
+	return &Daemon{
+		services: services,
+		loop:     services.Loop,
+		handler:  instance.NewHandler(services.Loop),
+	}
+}
+
+// Run serves the socket at path until ctx is cancelled. It returns
+// ipc.ErrAlreadyRunning if another karedoro instance is running.
+func (d *Daemon) Run(ctx context.Context, path string) error {
+	inst, err := instance.Acquire(path)
+	if err != nil {
+		return err
+	}
+	defer inst.Close()
+	
+	d.services.AttachIntegrations()
+	if d.services.Audio != nil && d.services.Notification != nil {
+		d.services.NewFeedbackHandler().Attach(d.services.Session)
+	}
+	
+	if d.services.Config != nil {
+		api, err := httpapi.Start(d.services)
+		if err != nil {
+			log.Printf("Warning: http api disabled: %v", err)
+		} else if api != nil {
+			d.handler.SetPairingCode(api.PairingCode)
+			defer api.Close()
+		}
+	}
+	
+	if d.services.Config != nil {
+		bus, err := dbusapi.Start(d.services)
+		if err != nil {
+			log.Printf("Warning: d-bus interface disabled: %v", err)
+		}
+		if bus != nil {
+			defer bus.Close()
+		}
+	}
+	
+	if err := inst.Listen(path, d.Handle); err != nil {
+		return err
+	}
+	inst.Publish(d.services.Session)
+	log.Printf("karedoro daemon listening on %s", path)
+	
+	d.loop.Run(ctx)
+	return nil
+}
+
+// Handle runs a request on the session's loop.
+func (d *Daemon) Handle(req ipc.Request) ipc.Response {
+	return d.handler.Handle(req)
+}
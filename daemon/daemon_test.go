@@ -0,0 +1,63 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"karedoro/application"
+	"karedoro/domain"
+	"karedoro/ipc"
+)
+
+func TestDaemon_Run(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run", "karedoro.sock")
+	services := &application.Services{Session: application.NewSessionService()}
+	d := New(services)
+	
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- d.Run(ctx, path)
+	}()
+	
+	var resp ipc.Response
+	var err error
+	for i := 0; i < 50; i++ {
+		resp, err = ipc.Call(path, ipc.Request{Command: ipc.CommandStart, Args: []string{"work"}})
+		if !errors.Is(err, ipc.ErrNotRunning) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatalf("Call should not return error, got %v", err)
+	}
+	if resp.Status.State != domain.WorkSession.String() {
+		t.Errorf("Expected WorkSession, got %v", resp.Status.State)
+	}
+	
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Run should not return error, got %v", err)
+	}
+	if resp := d.Handle(ipc.Request{Command: ipc.CommandStatus}); resp.OK {
+		t.Error("Handle should fail once the daemon has stopped")
+	}
+}
+
+func TestDaemon_RunAlreadyRunning(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run", "karedoro.sock")
+	lock, err := ipc.AcquireLock(ipc.LockPath(path))
+	if err != nil {
+		t.Fatalf("AcquireLock should not return error, got %v", err)
+	}
+	defer lock.Release()
+	
+	d := New(&application.Services{Session: application.NewSessionService()})
+	if err := d.Run(context.Background(), path); !errors.Is(err, ipc.ErrAlreadyRunning) {
+		t.Errorf("Expected ErrAlreadyRunning, got %v", err)
+	}
+}
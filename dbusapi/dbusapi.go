@@ -0,0 +1,256 @@
+//go:build linux || freebsd || openbsd || netbsd || dragonfly
+
+// Package dbusapi exposes the timer on the D-Bus session bus as
+// org.karedoro.Timer, for desktop shell extensions, plasmoids and scripts.
+package dbusapi
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/godbus/dbus/v5/introspect"
+
+	"karedoro/application"
+	"karedoro/ipc"
+)
+
+// Names on the bus.
+const (
+	BusName       = "org.karedoro.Timer"
+	ObjectPath    = dbus.ObjectPath("/org/karedoro/Timer")
+	InterfaceName = "org.karedoro.Timer"
+	
+	errorInvalidState = InterfaceName + ".Error.InvalidState"
+	errorUnavailable  = InterfaceName + ".Error.Unavailable"
+	
+	propertiesInterface = "org.freedesktop.DBus.Properties"
+)
+
+// ErrNameTaken is returned when another process owns BusName.
+var ErrNameTaken = errors.New("org.karedoro.Timer is already owned on the session bus")
+
+// introspection describes the object for tools such as busctl and d-feet.
+var introspection = &introspect.Interface{
+	Name: InterfaceName,
+	Methods: []introspect.Method{
+		{Name: "StartWork"},
+		{Name: "StartBreak"},
+		{Name: "StartFlow"},
+		{Name: "Pause"},
+		{Name: "Resume"},
+		{Name: "Abandon"},
+	},
+	Properties: []introspect.Property{
+		{Name: "State", Type: "s", Access: "read"},
+		{Name: "SessionType", Type: "s", Access: "read"},
+		{Name: "Remaining", Type: "i", Access: "read"},
+		{Name: "Paused", Type: "b", Access: "read"},
+	},
+	Signals: []introspect.Signal{
+		{Name: "StateChanged", Args: []introspect.Arg{
+			{Name: "event", Type: "s"},
+			{Name: "state", Type: "s"},
+			{Name: "session_type", Type: "s"},
+			{Name: "remaining", Type: "i"},
+		}},
+	},
+}
+
+// Runner runs a function on the goroutine that owns the session.
+type Runner interface {
+	Do(func(*application.SessionService)) error
+}
+
+// Server is the exported Timer object.
+type Server struct {
+	conn   *dbus.Conn
+	runner Runner
+}
+
+// Start connects to the session bus and gives the desktop notifications
+// action buttons that work from the connection. It also exports the Timer if
+// it is enabled in the config. The returned Server holds the connection even
+// when the Timer could not be exported, so that the buttons keep working.
+func Start(services *application.Services) (*Server, error) {
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return nil, err
+	}
+	return attach(conn, services)
+}
+
+// attach installs the notifier on conn and exports the Timer if enabled.
+func attach(conn *dbus.Conn, services *application.Services) (*Server, error) {
+	if notifications, ok := services.Notification.(*application.NotificationService); ok {
+		notifier, err := NewNotifier(conn, services.Loop)
+		if err != nil {
+			log.Printf("Warning: notification buttons disabled: %v", err)
+		} else {
+			notifications.SetNotifier(notifier)
+		}
+	}
+	
+	// 通知のボタンは Timer の公開とは別に、設定にかかわらず使う
+	if services.Config != nil && !services.Config.GetConfig().DBus.Enabled {
+		return &Server{conn: conn, runner: services.Loop}, nil
+	}
+	server, err := Serve(conn, services.Session, services.Loop)
+	if err != nil {
+		return &Server{conn: conn, runner: services.Loop}, err
+	}
+	return server, nil
+}
+
+// Serve exports the Timer on conn and claims BusName. It subscribes to the
+// session's events, so it must be called before the session's loop starts.
+func Serve(conn *dbus.Conn, sessionService *application.SessionService, runner Runner) (*Server, error) {
+	s := &Server{conn: conn, runner: runner}
+	
+	if err := conn.Export(timer{s}, ObjectPath, InterfaceName); err != nil {
+		return nil, err
+	}
+	if err := conn.Export(properties{s}, ObjectPath, propertiesInterface); err != nil {
+		return nil, err
+	}
+	node := &introspect.Node{
+		Name: string(ObjectPath),
+		Interfaces: []introspect.Interface{
+			introspect.IntrospectData,
+			{Name: propertiesInterface, Methods: introspect.Methods(properties{})},
+			*introspection,
+		},
+	}
+	if err := conn.Export(introspect.NewIntrospectable(node), ObjectPath, "org.freedesktop.DBus.Introspectable"); err != nil {
+		return nil, err
+	}
+	
+	reply, err := conn.RequestName(BusName, dbus.NameFlagDoNotQueue)
+	if err != nil {
+		return nil, err
+	}
+	if reply != dbus.RequestNameReplyPrimaryOwner {
+		return nil, ErrNameTaken
+	}
+	
+	sessionService.AddEventListener(func(event string) {
+		s.emit(event, sessionService.Status())
+	})
+	return s, nil
+}
+
+// Close releases the name and disconnects from the bus.
+func (s *Server) Close() error {
+	s.conn.ReleaseName(BusName)
+	return s.conn.Close()
+}
+
+// emit announces an event: StateChanged for every event, and
+// PropertiesChanged so that property caches stay current.
+func (s *Server) emit(event string, status application.Status) {
+	if err := s.conn.Emit(ObjectPath, InterfaceName+".StateChanged", event, status.State, status.SessionType, int32(status.Remaining)); err != nil {
+		log.Printf("dbus: %v", err)
+		return
+	}
+	
+	changed := map[string]dbus.Variant{
+		"State":       dbus.MakeVariant(status.State),
+		"SessionType": dbus.MakeVariant(status.SessionType),
+		"Paused":      dbus.MakeVariant(status.Paused),
+	}
+	// 残り時間は常に変化するので値は送らず無効化だけ通知する
+	s.conn.Emit(ObjectPath, propertiesInterface+".PropertiesChanged", InterfaceName, changed, []string{"Remaining"})
+}
+
+// status reads the current status on the session's goroutine.
+func (s *Server) status() (application.Status, *dbus.Error) {
+	var status application.Status
+	if err := s.runner.Do(func(sessionService *application.SessionService) {
+		status = sessionService.Status()
+	}); err != nil {
+		return status, dbus.NewError(errorUnavailable, []interface{}{err.Error()})
+	}
+	return status, nil
+}
+
+func (s *Server) apply(req ipc.Request) *dbus.Error {
+	var err error
+	if runErr := s.runner.Do(func(sessionService *application.SessionService) {
+		err = ipc.Apply(sessionService, req)
+	}); runErr != nil {
+		return dbus.NewError(errorUnavailable, []interface{}{runErr.Error()})
+	}
+	if err != nil {
+		return dbus.NewError(errorInvalidState, []interface{}{err.Error()})
+	}
+	return nil
+}
+
+// timer holds the org.karedoro.Timer methods. Only methods returning
+// *dbus.Error are exported, so it is kept apart from Server.
+type timer struct {
+	s *Server
+}
+
+func (t timer) StartWork() *dbus.Error {
+	return t.s.apply(ipc.Request{Command: ipc.CommandStart, Args: []string{"work"}})
+}
+
+func (t timer) StartBreak() *dbus.Error {
+	return t.s.apply(ipc.Request{Command: ipc.CommandStart, Args: []string{"break"}})
+}
+
+func (t timer) StartFlow() *dbus.Error {
+	return t.s.apply(ipc.Request{Command: ipc.CommandStart, Args: []string{"flow"}})
+}
+
+func (t timer) Pause() *dbus.Error {
+	return t.s.apply(ipc.Request{Command: ipc.CommandPause})
+}
+
+func (t timer) Resume() *dbus.Error {
+	return t.s.apply(ipc.Request{Command: ipc.CommandResume})
+}
+
+func (t timer) Abandon() *dbus.Error {
+	return t.s.apply(ipc.Request{Command: ipc.CommandAbandon})
+}
+
+// properties implements org.freedesktop.DBus.Properties. Values are read
+// when asked for, so Remaining is always current.
+type properties struct {
+	s *Server
+}
+
+func (p properties) Get(iface, name string) (dbus.Variant, *dbus.Error) {
+	all, err := p.GetAll(iface)
+	if err != nil {
+		return dbus.Variant{}, err
+	}
+	value, ok := all[name]
+	if !ok {
+		return dbus.Variant{}, dbus.NewError("org.freedesktop.DBus.Error.UnknownProperty", []interface{}{fmt.Sprintf("no property %s", name)})
+	}
+	return value, nil
+}
+
+func (p properties) GetAll(iface string) (map[string]dbus.Variant, *dbus.Error) {
+	if iface != InterfaceName {
+		return nil, dbus.NewError("org.freedesktop.DBus.Error.UnknownInterface", []interface{}{iface})
+	}
+	status, err := p.s.status()
+	if err != nil {
+		return nil, err
+	}
+	return map[string]dbus.Variant{
+		"State":       dbus.MakeVariant(status.State),
+		"SessionType": dbus.MakeVariant(status.SessionType),
+		"Remaining":   dbus.MakeVariant(int32(status.Remaining)),
+		"Paused":      dbus.MakeVariant(status.Paused),
+	}, nil
+}
+
+func (p properties) Set(iface, name string, value dbus.Variant) *dbus.Error {
+	return dbus.NewError("org.freedesktop.DBus.Error.PropertyReadOnly", []interface{}{name})
+}
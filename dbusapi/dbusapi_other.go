@@ -0,0 +1,17 @@
+//go:build !linux && !freebsd && !openbsd && !netbsd && !dragonfly
+
+package dbusapi
+
+import "karedoro/application"
+
+// Server is not available without a D-Bus session bus.
+type Server struct{}
+
+// Start does nothing on platforms without a session bus.
+func Start(services *application.Services) (*Server, error) {
+	return nil, nil
+}
+
+func (s *Server) Close() error {
+	return nil
+}
@@ -0,0 +1,147 @@
+//go:build linux || freebsd || openbsd || netbsd || dragonfly
+
+package dbusapi
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+
+	"karedoro/application"
+	"karedoro/domain"
+)
+
+const busConfig = `<!DOCTYPE busconfig PUBLIC "-//freedesktop//DTD D-Bus Bus Configuration 1.0//EN"
+ "http://www.freedesktop.org/standards/dbus/1.0/busconfig.dtd">
+<busconfig>
+  <type>session</type>
+  <listen>unix:dir=%DIR%</listen>
+  <auth>EXTERNAL</auth>
+  <policy context="default">
+    <allow send_destination="*" eavesdrop="true"/>
+    <allow eavesdrop="true"/>
+    <allow own="*"/>
+  </policy>
+</busconfig>
+`
+
+// startBus runs a private dbus-daemon for the test and returns its address.
+func startBus(t *testing.T) string {
+	t.Helper()
+	daemon, err := exec.LookPath("dbus-daemon")
+	if err != nil {
+		t.Skip("dbus-daemon is not installed")
+	}
+	
+	dir := t.TempDir()
+	config := filepath.Join(dir, "bus.conf")
+	if err := os.WriteFile(config, []byte(strings.ReplaceAll(busConfig, "%DIR%", dir)), 0600); err != nil {
+		t.Fatalf("Failed to write bus config: %v", err)
+	}
+	
+	cmd := exec.Command(daemon, "--config-file="+config, "--print-address", "--nofork")
+	stdout, _ := cmd.StdoutPipe()
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("Failed to start dbus-daemon: %v", err)
+	}
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	})
+	
+	address, err := bufio.NewReader(stdout).ReadString('\n')
+	if err != nil {
+		t.Fatalf("Failed to read the bus address: %v", err)
+	}
+	return strings.TrimSpace(address)
+}
+
+func connect(t *testing.T, address string) *dbus.Conn {
+	t.Helper()
+	conn, err := dbus.Connect(address)
+	if err != nil {
+		t.Fatalf("Failed to connect to the bus: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestServer_MethodsPropertiesAndSignals(t *testing.T) {
+	address := startBus(t)
+	
+	sessionService := application.NewSessionService()
+	loop := application.NewLoop(sessionService, time.Millisecond)
+	server, err := Serve(connect(t, address), sessionService, loop)
+	if err != nil {
+		t.Fatalf("Serve should not return error, got %v", err)
+	}
+	defer server.Close()
+	
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go loop.Run(ctx)
+	
+	client := connect(t, address)
+	signals := make(chan *dbus.Signal, 10)
+	client.Signal(signals)
+	if err := client.AddMatchSignal(dbus.WithMatchInterface(InterfaceName), dbus.WithMatchMember("StateChanged")); err != nil {
+		t.Fatalf("AddMatchSignal should not return error, got %v", err)
+	}
+	
+	timer := client.Object(BusName, ObjectPath)
+	if err := timer.Call(InterfaceName+".StartWork", 0).Err; err != nil {
+		t.Fatalf("StartWork should not return error, got %v", err)
+	}
+	
+	select {
+	case signal := <-signals:
+		if signal.Body[0] != domain.EventWorkSessionStart || signal.Body[1] != domain.WorkSession.String() {
+			t.Errorf("Unexpected StateChanged %v", signal.Body)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("StateChanged was not emitted")
+	}
+	
+	state, err := timer.GetProperty(InterfaceName + ".State")
+	if err != nil || state.Value() != domain.WorkSession.String() {
+		t.Errorf("Expected State WorkSession, got %v (%v)", state, err)
+	}
+	remaining, err := timer.GetProperty(InterfaceName + ".Remaining")
+	if err != nil || remaining.Value().(int32) <= 0 {
+		t.Errorf("Expected positive Remaining, got %v (%v)", remaining, err)
+	}
+	
+	if err := timer.Call(InterfaceName+".StartBreak", 0).Err; err == nil {
+		t.Error("StartBreak should fail while working")
+	}
+	if err := timer.Call(InterfaceName+".Pause", 0).Err; err != nil {
+		t.Errorf("Pause should not return error, got %v", err)
+	}
+	paused, _ := timer.GetProperty(InterfaceName + ".Paused")
+	if paused.Value() != true {
+		t.Errorf("Expected Paused true, got %v", paused)
+	}
+}
+
+func TestServe_NameTaken(t *testing.T) {
+	address := startBus(t)
+	
+	sessionService := application.NewSessionService()
+	loop := application.NewLoop(sessionService, time.Millisecond)
+	server, err := Serve(connect(t, address), sessionService, loop)
+	if err != nil {
+		t.Fatalf("Serve should not return error, got %v", err)
+	}
+	t.Cleanup(func() { server.Close() })
+	
+	if _, err := Serve(connect(t, address), sessionService, loop); err != ErrNameTaken {
+		t.Errorf("Expected ErrNameTaken, got %v", err)
+	}
+}
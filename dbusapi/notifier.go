@@ -0,0 +1,160 @@
+//go:build linux || freebsd || openbsd || netbsd || dragonfly
+
+package dbusapi
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+
+	"karedoro/application"
+)
+
+// The freedesktop notification server.
+const (
+	notificationsName = "org.freedesktop.Notifications"
+	notificationsPath = dbus.ObjectPath("/org/freedesktop/Notifications")
+	
+	appName = "karedoro"
+)
+
+// Notifier shows notifications through org.freedesktop.Notifications, with
+// action buttons, urgency, replacement and expiry. A pressed button runs its
+// action on the session's goroutine.
+type Notifier struct {
+	conn    *dbus.Conn
+	runner  Runner
+	signals chan *dbus.Signal
+	
+	mu      sync.Mutex
+	tags    map[string]uint32
+	actions map[uint32][]application.NotificationAction
+}
+
+// NewNotifier subscribes to the notification server's signals on conn. It
+// stops listening when conn is closed.
+func NewNotifier(conn *dbus.Conn, runner Runner) (*Notifier, error) {
+	for _, member := range []string{"ActionInvoked", "NotificationClosed"} {
+		if err := conn.AddMatchSignal(
+			dbus.WithMatchObjectPath(notificationsPath),
+			dbus.WithMatchInterface(notificationsName),
+			dbus.WithMatchMember(member),
+		); err != nil {
+			return nil, err
+		}
+	}
+	
+	n := &Notifier{
+		conn:    conn,
+		runner:  runner,
+		signals: make(chan *dbus.Signal, 16),
+		tags:    make(map[string]uint32),
+		actions: make(map[uint32][]application.NotificationAction),
+	}
+	conn.Signal(n.signals)
+	go n.watch()
+	return n, nil
+}
+
+// Notify shows the notification, replacing the last one with the same tag.
+func (n *Notifier) Notify(notification application.Notification) error {
+	n.mu.Lock()
+	replaces := n.tags[notification.Tag]
+	n.mu.Unlock()
+	
+	actions := make([]string, 0, 2*len(notification.Actions))
+	for _, action := range notification.Actions {
+		actions = append(actions, action.ID, action.Label)
+	}
+	hints := map[string]dbus.Variant{
+		"urgency": dbus.MakeVariant(byte(notification.Urgency)),
+	}
+	
+	var id uint32
+	err := n.conn.Object(notificationsName, notificationsPath).Call(
+		notificationsName+".Notify", 0,
+		appName, replaces, "", notification.Title, notification.Body, actions, hints, expireTimeout(notification.Expire),
+	).Store(&id)
+	if err != nil {
+		return err
+	}
+	
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if notification.Tag != "" {
+		n.tags[notification.Tag] = id
+	}
+	delete(n.actions, replaces)
+	if len(notification.Actions) > 0 {
+		n.actions[id] = notification.Actions
+	}
+	return nil
+}
+
+// expireTimeout converts an expiry to milliseconds, where -1 leaves it to
+// the server and 0 never expires.
+func expireTimeout(expire time.Duration) int32 {
+	switch {
+	case expire == 0:
+		return -1
+	case expire < 0:
+		return 0
+	default:
+		return int32(expire / time.Millisecond)
+	}
+}
+
+func (n *Notifier) watch() {
+	for signal := range n.signals {
+		var id uint32
+		switch signal.Name {
+		case notificationsName + ".ActionInvoked":
+			var key string
+			if err := dbus.Store(signal.Body, &id, &key); err == nil {
+				n.invoke(id, key)
+			}
+		case notificationsName + ".NotificationClosed":
+			var reason uint32
+			if err := dbus.Store(signal.Body, &id, &reason); err == nil {
+				n.forget(id)
+			}
+		}
+	}
+}
+
+// invoke runs the action behind a pressed button. Signals for other
+// applications' notifications are ignored, since their ids are unknown.
+func (n *Notifier) invoke(id uint32, key string) {
+	n.mu.Lock()
+	actions := n.actions[id]
+	n.mu.Unlock()
+	
+	for _, action := range actions {
+		if action.ID != key {
+			continue
+		}
+		var err error
+		if runErr := n.runner.Do(func(sessionService *application.SessionService) {
+			err = action.Run(sessionService)
+		}); runErr != nil {
+			err = runErr
+		}
+		if err != nil {
+			log.Printf("notification action %s: %v", key, err)
+		}
+		return
+	}
+}
+
+func (n *Notifier) forget(id uint32) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	delete(n.actions, id)
+	for tag, tagged := range n.tags {
+		if tagged == id {
+			delete(n.tags, tag)
+		}
+	}
+}
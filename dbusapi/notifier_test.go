@@ -0,0 +1,164 @@
+//go:build linux || freebsd || openbsd || netbsd || dragonfly
+
+package dbusapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+
+	"karedoro/application"
+	"karedoro/domain"
+)
+
+type notifyCall struct {
+	replaces uint32
+	actions  []string
+	hints    map[string]dbus.Variant
+	expire   int32
+}
+
+// fakeNotifications stands in for the desktop's notification server.
+type fakeNotifications struct {
+	lastID uint32
+	calls  chan notifyCall
+}
+
+func (f *fakeNotifications) Notify(appName string, replaces uint32, icon, summary, body string, actions []string, hints map[string]dbus.Variant, expire int32) (uint32, *dbus.Error) {
+	f.calls <- notifyCall{replaces: replaces, actions: actions, hints: hints, expire: expire}
+	if replaces != 0 {
+		return replaces, nil
+	}
+	f.lastID++
+	return f.lastID, nil
+}
+
+func TestNotifier_ActionsAndReplacement(t *testing.T) {
+	address := startBus(t)
+	
+	server := connect(t, address)
+	fake := &fakeNotifications{calls: make(chan notifyCall, 10)}
+	if err := server.Export(fake, notificationsPath, notificationsName); err != nil {
+		t.Fatalf("Export should not return error, got %v", err)
+	}
+	if _, err := server.RequestName(notificationsName, dbus.NameFlagDoNotQueue); err != nil {
+		t.Fatalf("RequestName should not return error, got %v", err)
+	}
+	
+	sessionService := application.NewSessionService()
+	config := application.DefaultConfig()
+	config.WorkDuration = 20 * time.Millisecond
+	sessionService.Configure(config)
+	loop := application.NewLoop(sessionService, time.Millisecond)
+	
+	notifier, err := NewNotifier(connect(t, address), loop)
+	if err != nil {
+		t.Fatalf("NewNotifier should not return error, got %v", err)
+	}
+	notifications := application.NewNotificationService()
+	notifications.SetNotifier(notifier)
+	
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go loop.Run(ctx)
+	
+	loop.Do(func(s *application.SessionService) { s.StartWorkSession() })
+	time.Sleep(50 * time.Millisecond)
+	
+	if err := notifications.ShowWorkSessionEnd(); err != nil {
+		t.Fatalf("ShowWorkSessionEnd should not return error, got %v", err)
+	}
+	first := <-fake.calls
+	if first.replaces != 0 || first.expire != 0 {
+		t.Errorf("Expected a new notification that never expires, got %+v", first)
+	}
+	if len(first.actions) != 4 || first.actions[0] != "start-break" || first.actions[2] != "skip-break" {
+		t.Errorf("Expected Start Break and Skip buttons, got %v", first.actions)
+	}
+	if first.hints["urgency"].Value() != byte(application.UrgencyNormal) {
+		t.Errorf("Expected normal urgency, got %v", first.hints["urgency"])
+	}
+	
+	notifications.ShowWorkSessionEnd()
+	if second := <-fake.calls; second.replaces != fake.lastID {
+		t.Errorf("Expected the second notification to replace %d, got %d", fake.lastID, second.replaces)
+	}
+	
+	if err := server.Emit(notificationsPath, notificationsName+".ActionInvoked", fake.lastID, "start-break"); err != nil {
+		t.Fatalf("Emit should not return error, got %v", err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		var state domain.SessionState
+		loop.Do(func(s *application.SessionService) { state = s.GetSession().GetState() })
+		if state == domain.BreakSession {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected Start Break to start the break, got %v", state)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestAttach_ButtonsWithoutTimer(t *testing.T) {
+	address := startBus(t)
+	
+	server := connect(t, address)
+	fake := &fakeNotifications{calls: make(chan notifyCall, 10)}
+	if err := server.Export(fake, notificationsPath, notificationsName); err != nil {
+		t.Fatalf("Export should not return error, got %v", err)
+	}
+	if _, err := server.RequestName(notificationsName, dbus.NameFlagDoNotQueue); err != nil {
+		t.Fatalf("RequestName should not return error, got %v", err)
+	}
+	
+	t.Setenv("HOME", t.TempDir())
+	sessionService := application.NewSessionService()
+	services := &application.Services{
+		Session:      sessionService,
+		Notification: application.NewNotificationService(),
+		Config:       application.NewConfigService(),
+		Loop:         application.NewLoop(sessionService, time.Millisecond),
+	}
+	services.Config.GetConfig().DBus.Enabled = false
+	
+	bus, err := attach(connect(t, address), services)
+	if err != nil {
+		t.Fatalf("attach should not return error, got %v", err)
+	}
+	defer bus.Close()
+	
+	// Timer は公開しないが、通知のボタンは付く
+	var owned bool
+	if err := server.BusObject().Call("org.freedesktop.DBus.NameHasOwner", 0, BusName).Store(&owned); err != nil || owned {
+		t.Errorf("Expected %s not to be owned with dbus disabled, got %v (%v)", BusName, owned, err)
+	}
+	services.Notification.ShowWorkSessionEnd()
+	select {
+	case call := <-fake.calls:
+		if len(call.actions) == 0 {
+			t.Error("Expected the notification to have buttons")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("The notification did not reach the notification server")
+	}
+}
+
+func TestExpireTimeout(t *testing.T) {
+	tests := []struct {
+		expire time.Duration
+		want   int32
+	}{
+		{0, -1},
+		{application.NeverExpire, 0},
+		{5 * time.Second, 5000},
+	}
+	for _, tt := range tests {
+		if got := expireTimeout(tt.expire); got != tt.want {
+			t.Errorf("expireTimeout(%v) = %d, want %d", tt.expire, got, tt.want)
+		}
+	}
+}
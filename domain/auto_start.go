@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// AutoStartPolicy starts the next session in the work/break cycle automatically
+// once a session has ended and Delay has passed without the user choosing.
+type AutoStartPolicy struct {
+	Enabled bool          `json:"enabled"`
+	Delay   time.Duration `json:"delay"`
+}
+
+// DefaultAutoStartPolicy returns the auto-start settings used when nothing is configured.
+func DefaultAutoStartPolicy() AutoStartPolicy {
+	return AutoStartPolicy{
+		Enabled: false,
+		Delay:   30 * time.Second,
+	}
+}
+
+// Validate checks that an enabled policy has a positive delay.
+func (p AutoStartPolicy) Validate() error {
+	if p.Enabled && p.Delay <= 0 {
+		return fmt.Errorf("%w: auto-start delay must be positive", ErrInvalidConfig)
+	}
+	return nil
+}
+
+// Next returns the session type that follows t in the cycle. A break is
+// followed by work; use Session.NextSessionType to continue a flowtime cycle.
+func (t SessionType) Next() SessionType {
+	if t == Work || t == Flow {
+		return Break
+	}
+	return Work
+}
@@ -60,6 +60,23 @@ var (
 	ErrInvalidConfig     = errors.New("invalid configuration")
 )
 
+// Pause budget errors.
+var (
+	ErrPauseDisabled        = errors.New("pausing is disabled in strict mode")
+	ErrPauseBudgetExhausted = errors.New("pause budget exhausted")
+)
+
+// Skip allowance errors.
+var (
+	ErrNoBreakDue        = errors.New("no break is due")
+	ErrSkipLimitReached  = errors.New("daily skip allowance used up")
+)
+
+// Extension errors.
+var (
+	ErrExtendLimitReached = errors.New("extension limit reached")
+)
+
 // Audio service errors.
 var (
 	ErrAudioNotReady     = errors.New("audio service not ready")
@@ -0,0 +1,144 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// ExtendPolicy limits how a running session may be extended. Step is the
+// amount added by the quick-extend hotkey; zero limits mean unlimited.
+type ExtendPolicy struct {
+	Step          time.Duration `json:"step"`
+	MaxPerSession int           `json:"max_per_session"`
+	MaxPerDay     int           `json:"max_per_day"`
+	MaxExtension  time.Duration `json:"max_extension"`
+}
+
+// DefaultExtendPolicy returns the extension limits used when nothing is configured.
+func DefaultExtendPolicy() ExtendPolicy {
+	return ExtendPolicy{
+		Step:          2 * time.Minute,
+		MaxPerSession: 2,
+		MaxPerDay:     6,
+		MaxExtension:  10 * time.Minute,
+	}
+}
+
+// Validate checks that the step fits within the per-extension maximum.
+func (p ExtendPolicy) Validate() error {
+	if p.Step <= 0 {
+		return fmt.Errorf("%w: extend step must be positive", ErrInvalidConfig)
+	}
+	if p.MaxPerSession < 0 || p.MaxPerDay < 0 || p.MaxExtension < 0 {
+		return fmt.Errorf("%w: extend limits must not be negative", ErrInvalidConfig)
+	}
+	if p.MaxExtension > 0 && p.Step > p.MaxExtension {
+		return fmt.Errorf("%w: extend step %v exceeds max extension %v", ErrInvalidConfig, p.Step, p.MaxExtension)
+	}
+	return nil
+}
+
+// SetExtendPolicy replaces the extension limits. Extensions already taken
+// still count against them.
+func (s *Session) SetExtendPolicy(policy ExtendPolicy) error {
+	if err := policy.Validate(); err != nil {
+		return NewSessionError("set extend policy", err)
+	}
+
+	s.extendPolicy = policy
+	return nil
+}
+
+func (s *Session) GetExtendPolicy() ExtendPolicy {
+	return s.extendPolicy
+}
+
+// RestoreExtensions seeds today's extension count, e.g. from persisted
+// statistics. Counts recorded for any other day are ignored.
+func (s *Session) RestoreExtensions(day time.Time, count int) {
+	if dayKey(day) != dayKey(time.Now()) {
+		return
+	}
+	s.extendDay = dayKey(day)
+	s.extensionsToday = count
+}
+
+// ExtendSession adds d to the running work or break session.
+func (s *Session) ExtendSession(d time.Duration) error {
+	if s.state != WorkSession && s.state != BreakSession {
+		return NewSessionError("extend", ErrInvalidState)
+	}
+	if d <= 0 || (s.extendPolicy.MaxExtension > 0 && d > s.extendPolicy.MaxExtension) {
+		return NewSessionError("extend", ErrInvalidDuration)
+	}
+	if !s.CanExtend() {
+		return NewSessionError("extend", ErrExtendLimitReached)
+	}
+
+	s.currentTimer.Extend(d)
+	s.extensions++
+	s.extendedBy += d
+	s.extensionsToday++
+	s.lastExtension = d
+	return nil
+}
+
+// CanExtend reports whether the running session may be extended right now.
+func (s *Session) CanExtend() bool {
+	if s.state != WorkSession && s.state != BreakSession {
+		return false
+	}
+	return s.ExtensionsRemaining() != 0
+}
+
+// ExtensionsRemaining returns how many more times the running session may be
+// extended, or -1 if unlimited.
+func (s *Session) ExtensionsRemaining() int {
+	remaining := -1
+	if s.extendPolicy.MaxPerSession > 0 {
+		remaining = s.extendPolicy.MaxPerSession - s.extensions
+	}
+	if s.extendPolicy.MaxPerDay > 0 {
+		s.rollExtendDay()
+		if today := s.extendPolicy.MaxPerDay - s.extensionsToday; remaining < 0 || today < remaining {
+			remaining = today
+		}
+	}
+	if remaining < 0 && (s.extendPolicy.MaxPerSession > 0 || s.extendPolicy.MaxPerDay > 0) {
+		return 0
+	}
+	return remaining
+}
+
+// Extensions returns how many times the running session has been extended.
+func (s *Session) Extensions() int {
+	return s.extensions
+}
+
+// ExtendedBy returns the total time added to the running session.
+func (s *Session) ExtendedBy() time.Duration {
+	return s.extendedBy
+}
+
+// ExtensionsToday returns how many extensions have been taken today.
+func (s *Session) ExtensionsToday() int {
+	s.rollExtendDay()
+	return s.extensionsToday
+}
+
+// LastExtension returns the length of the most recent extension.
+func (s *Session) LastExtension() time.Duration {
+	return s.lastExtension
+}
+
+func (s *Session) resetExtensions() {
+	s.extensions = 0
+	s.extendedBy = 0
+}
+
+func (s *Session) rollExtendDay() {
+	if today := dayKey(time.Now()); today != s.extendDay {
+		s.extendDay = today
+		s.extensionsToday = 0
+	}
+}
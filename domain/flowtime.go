@@ -0,0 +1,152 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// FlowBreakStep maps flow sessions shorter than UpTo to a break of length
+// Break. A zero UpTo matches any length and must come last.
+type FlowBreakStep struct {
+	UpTo  time.Duration `json:"up_to"`
+	Break time.Duration `json:"break"`
+}
+
+// FlowtimePolicy decides how long the break after a flow session is. If
+// BreakTable is set it is used; otherwise the break is BreakFraction of the
+// time worked, never shorter than MinBreak.
+type FlowtimePolicy struct {
+	BreakFraction float64         `json:"break_fraction"`
+	MinBreak      time.Duration   `json:"min_break"`
+	BreakTable    []FlowBreakStep `json:"break_table"`
+}
+
+// DefaultFlowtimePolicy returns the flowtime settings used when nothing is configured.
+func DefaultFlowtimePolicy() FlowtimePolicy {
+	return FlowtimePolicy{
+		BreakFraction: 0.2,
+		MinBreak:      time.Minute,
+		BreakTable: []FlowBreakStep{
+			{UpTo: 25 * time.Minute, Break: 5 * time.Minute},
+			{UpTo: 50 * time.Minute, Break: 8 * time.Minute},
+			{UpTo: 90 * time.Minute, Break: 10 * time.Minute},
+			{UpTo: 0, Break: 15 * time.Minute},
+		},
+	}
+}
+
+// Validate checks the fraction and that the table is ordered.
+func (p FlowtimePolicy) Validate() error {
+	if p.BreakFraction < 0 || p.MinBreak < 0 {
+		return fmt.Errorf("%w: flowtime break settings must not be negative", ErrInvalidConfig)
+	}
+	if len(p.BreakTable) == 0 && p.BreakFraction == 0 && p.MinBreak == 0 {
+		return fmt.Errorf("%w: flowtime needs a break fraction or table", ErrInvalidConfig)
+	}
+
+	var previous time.Duration
+	for i, step := range p.BreakTable {
+		if step.Break <= 0 {
+			return fmt.Errorf("%w: flowtime break %d must be positive", ErrInvalidConfig, i)
+		}
+		if step.UpTo == 0 {
+			if i != len(p.BreakTable)-1 {
+				return fmt.Errorf("%w: open-ended flowtime step %d must be last", ErrInvalidConfig, i)
+			}
+			continue
+		}
+		if step.UpTo <= previous {
+			return fmt.Errorf("%w: flowtime step %d must come after %v", ErrInvalidConfig, i, previous)
+		}
+		previous = step.UpTo
+	}
+	return nil
+}
+
+// BreakFor returns the break earned by a flow session of the given length.
+func (p FlowtimePolicy) BreakFor(worked time.Duration) time.Duration {
+	if len(p.BreakTable) > 0 {
+		for _, step := range p.BreakTable {
+			if step.UpTo == 0 || worked < step.UpTo {
+				return step.Break
+			}
+		}
+		return p.BreakTable[len(p.BreakTable)-1].Break
+	}
+
+	brk := time.Duration(float64(worked) * p.BreakFraction).Round(time.Second)
+	if brk < p.MinBreak {
+		return p.MinBreak
+	}
+	return brk
+}
+
+// SetFlowtimePolicy replaces the flowtime break rules.
+func (s *Session) SetFlowtimePolicy(policy FlowtimePolicy) error {
+	if err := policy.Validate(); err != nil {
+		return NewSessionError("set flowtime policy", err)
+	}
+
+	s.flowtimePolicy = policy
+	return nil
+}
+
+func (s *Session) GetFlowtimePolicy() FlowtimePolicy {
+	return s.flowtimePolicy
+}
+
+// StartFlowSession starts open-ended work that counts up until stopped.
+func (s *Session) StartFlowSession() error {
+	if s.state != Idle {
+		return nil
+	}
+	if err := s.consumeSkip(); err != nil {
+		return err
+	}
+
+	s.sessionType = Flow
+	s.workKind = Flow
+	s.currentTimer.StartCountUp()
+	s.stopWarnings()
+	s.resetPauseBudget()
+	s.resetExtensions()
+	s.CancelAutoStart()
+	s.resetOvertime()
+	s.setState(FlowSession)
+	return nil
+}
+
+// StopFlowSession ends a flow session and computes the break it has earned.
+func (s *Session) StopFlowSession() error {
+	if s.state != FlowSession {
+		return nil
+	}
+
+	s.endPause()
+	s.lastFlowWork = s.currentTimer.Elapsed()
+	s.pendingBreak = s.flowtimePolicy.BreakFor(s.lastFlowWork)
+	s.currentTimer.Stop()
+	s.breakDue = true
+	s.scheduleAutoStart()
+	s.setState(Idle)
+	s.startWarnings()
+	return nil
+}
+
+// FlowElapsed returns how long the current flow session has been running.
+func (s *Session) FlowElapsed() time.Duration {
+	if s.state != FlowSession {
+		return 0
+	}
+	return s.currentTimer.Elapsed()
+}
+
+// EarnedFlowBreak returns the break the current flow session would earn if stopped now.
+func (s *Session) EarnedFlowBreak() time.Duration {
+	return s.flowtimePolicy.BreakFor(s.FlowElapsed())
+}
+
+// LastFlowWork returns the length of the most recently finished flow session.
+func (s *Session) LastFlowWork() time.Duration {
+	return s.lastFlowWork
+}
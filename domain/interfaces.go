@@ -7,6 +7,8 @@ type AudioPlayer interface {
 	PlayStartSound() error
 	PlayEndSound() error
 	PlayWarningSound() error
+	PlayChimeSound() error
+	PlayAlarmSound() error
 	PlayBeep(frequency float64, duration time.Duration) error
 	IsReady() bool
 }
@@ -17,9 +19,13 @@ type NotificationSender interface {
 	ShowBreakSessionStart() error
 	ShowWorkSessionEnd() error
 	ShowBreakSessionEnd() error
+	ShowFlowSessionStart() error
+	ShowFlowSessionEnd() error
 	ShowWarning() error
 	ShowSessionPaused() error
 	ShowSessionResumed() error
+	ShowPauseReminder() error
+	ShowSessionAbandoned() error
 }
 
 // ConfigRepository handles configuration persistence.
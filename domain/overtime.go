@@ -0,0 +1,147 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// OvertimePolicy lets a finished work session continue as counted-up overtime.
+// MaxOvertime caps how long it may run before the overlay becomes mandatory;
+// BreakScale adds that fraction of the overtime to the following break.
+type OvertimePolicy struct {
+	Enabled     bool          `json:"enabled"`
+	MaxOvertime time.Duration `json:"max_overtime"`
+	BreakScale  float64       `json:"break_scale"`
+}
+
+// DefaultOvertimePolicy returns the overtime settings used when nothing is configured.
+func DefaultOvertimePolicy() OvertimePolicy {
+	return OvertimePolicy{
+		Enabled:     true,
+		MaxOvertime: 15 * time.Minute,
+		BreakScale:  0,
+	}
+}
+
+// Validate checks that an enabled policy has a positive cap and a sane scale.
+func (p OvertimePolicy) Validate() error {
+	if p.Enabled && p.MaxOvertime <= 0 {
+		return fmt.Errorf("%w: overtime cap must be positive", ErrInvalidConfig)
+	}
+	if p.BreakScale < 0 {
+		return fmt.Errorf("%w: overtime break scale must not be negative", ErrInvalidConfig)
+	}
+	return nil
+}
+
+// SetOvertimePolicy replaces the overtime settings.
+func (s *Session) SetOvertimePolicy(policy OvertimePolicy) error {
+	if err := policy.Validate(); err != nil {
+		return NewSessionError("set overtime policy", err)
+	}
+
+	s.overtimePolicy = policy
+	return nil
+}
+
+func (s *Session) GetOvertimePolicy() OvertimePolicy {
+	return s.overtimePolicy
+}
+
+// CanContinueOvertime reports whether the just-finished work session may be
+// continued. Once the cap has been hit the break can no longer be put off.
+func (s *Session) CanContinueOvertime() bool {
+	return s.overtimePolicy.Enabled && s.IsBreakDue() && s.sessionType == Work && !s.overtimeCapped
+}
+
+// ContinueOvertime keeps working after the work timer has run out.
+func (s *Session) ContinueOvertime() error {
+	if !s.CanContinueOvertime() {
+		return NewSessionError("continue overtime", ErrInvalidState)
+	}
+
+	s.stopWarnings()
+	s.CancelAutoStart()
+	s.overtimeTimer.Reset(s.overtimePolicy.MaxOvertime)
+	s.overtimeTimer.Start()
+	s.setState(Overtime)
+	return nil
+}
+
+// StopOvertime ends overtime and returns to the idle overlay with the break still due.
+func (s *Session) StopOvertime() error {
+	if s.state != Overtime {
+		return nil
+	}
+
+	s.finishOvertime()
+	s.setState(Idle)
+	s.startWarnings()
+	return nil
+}
+
+// OvertimeElapsed returns how long the current overtime has been running.
+func (s *Session) OvertimeElapsed() time.Duration {
+	if s.state != Overtime {
+		return 0
+	}
+	return s.overtimeTimer.Elapsed()
+}
+
+// OvertimeRemaining returns the time left before the overtime cap is reached.
+func (s *Session) OvertimeRemaining() time.Duration {
+	if s.state != Overtime {
+		return 0
+	}
+	return s.overtimeTimer.Remaining()
+}
+
+// GetOvertimeProgress returns how far the current overtime is towards its cap.
+func (s *Session) GetOvertimeProgress() float64 {
+	if s.state != Overtime {
+		return 0
+	}
+	return s.overtimeTimer.Progress()
+}
+
+// LastOvertime returns the length of the most recent overtime in this cycle.
+func (s *Session) LastOvertime() time.Duration {
+	return s.lastOvertime
+}
+
+// WasOvertimeCapped reports whether the last overtime ran into its cap.
+func (s *Session) WasOvertimeCapped() bool {
+	return s.overtimeCapped
+}
+
+// NextBreakDuration returns how long the next break will be, including any
+// extension earned through overtime.
+func (s *Session) NextBreakDuration() time.Duration {
+	if s.pendingBreak > 0 {
+		return s.pendingBreak
+	}
+	bonus := time.Duration(float64(s.lastOvertime) * s.overtimePolicy.BreakScale)
+	return s.breakDuration + bonus
+}
+
+func (s *Session) updateOvertime() {
+	s.overtimeTimer.Update()
+	if !s.overtimeTimer.IsFinished() {
+		return
+	}
+
+	s.finishOvertime()
+	s.overtimeCapped = true
+	s.setState(Idle)
+	s.startWarnings()
+}
+
+func (s *Session) finishOvertime() {
+	s.lastOvertime = s.overtimeTimer.Elapsed()
+	s.overtimeTimer.Stop()
+}
+
+func (s *Session) resetOvertime() {
+	s.lastOvertime = 0
+	s.overtimeCapped = false
+}
@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// PauseExhaustedAction decides what happens to a paused session once its
+// pause-time budget is used up.
+type PauseExhaustedAction int
+
+const (
+	PauseAutoResume PauseExhaustedAction = iota
+	PauseAbandon
+)
+
+func (a PauseExhaustedAction) String() string {
+	switch a {
+	case PauseAutoResume:
+		return "resume"
+	case PauseAbandon:
+		return "abandon"
+	default:
+		return "unknown"
+	}
+}
+
+func (a PauseExhaustedAction) MarshalText() ([]byte, error) {
+	if a != PauseAutoResume && a != PauseAbandon {
+		return nil, fmt.Errorf("%w: unknown pause action %d", ErrInvalidConfig, int(a))
+	}
+	return []byte(a.String()), nil
+}
+
+func (a *PauseExhaustedAction) UnmarshalText(text []byte) error {
+	switch strings.ToLower(strings.TrimSpace(string(text))) {
+	case "resume":
+		*a = PauseAutoResume
+	case "abandon":
+		*a = PauseAbandon
+	default:
+		return fmt.Errorf("%w: unknown pause action %q", ErrInvalidConfig, text)
+	}
+	return nil
+}
+
+// PausePolicy limits how a single session may be paused. Zero limits mean
+// unlimited; Strict disables pausing altogether.
+type PausePolicy struct {
+	Strict           bool                 `json:"strict"`
+	MaxPauses        int                  `json:"max_pauses"`
+	MaxPauseTime     time.Duration        `json:"max_pause_time"`
+	ReminderInterval time.Duration        `json:"reminder_interval"`
+	OnExhausted      PauseExhaustedAction `json:"on_exhausted"`
+}
+
+// DefaultPausePolicy returns the pause policy used when nothing is configured:
+// unlimited pauses without reminders, as before budgets existed. Budgets and
+// reminders are opt-in.
+func DefaultPausePolicy() PausePolicy {
+	return PausePolicy{
+		OnExhausted: PauseAutoResume,
+	}
+}
+
+// Validate checks that the limits are not negative.
+func (p PausePolicy) Validate() error {
+	if p.MaxPauses < 0 {
+		return fmt.Errorf("%w: max pauses must not be negative", ErrInvalidConfig)
+	}
+	if p.MaxPauseTime < 0 || p.ReminderInterval < 0 {
+		return fmt.Errorf("%w: pause durations must not be negative", ErrInvalidConfig)
+	}
+	return nil
+}
@@ -10,17 +10,190 @@ type Session struct {
 	warningTimer     *Timer
 	sessionType      SessionType
 	lastWarningTime  time.Time
+	workDuration     time.Duration
+	breakDuration    time.Duration
 	stateChangeCallbacks []func(SessionState, SessionState)
+	
+	// Idle-warning escalation. warningStep indexes the next rung to fire;
+	// once past the end of the ladder the last rung repeats every warningRepeat.
+	warningLadder []WarningStep
+	warningRepeat time.Duration
+	warningStep   int
+	
+	// Per-session pause budget.
+	pausePolicy        PausePolicy
+	pauseCount         int
+	pausedTotal        time.Duration
+	pauseStartedAt     time.Time
+	pauseReminderTimer *Timer
+	
+	abandoned bool
+	
+	// Break skipping. breakDue is set when a work session completes and
+	// cleared once the next session starts.
+	skipPolicy SkipPolicy
+	skipsToday int
+	skipDay    string
+	breakDue   bool
+	idleSince  time.Time
+	
+	// Auto-start of the next session after a countdown.
+	autoStartPolicy  AutoStartPolicy
+	autoStartTimer   *Timer
+	autoStartPending bool
+	
+	// Overtime after a finished work session. overtimeTimer counts down the
+	// cap; the overtime worked is its elapsed time.
+	overtimePolicy OvertimePolicy
+	overtimeTimer  *Timer
+	lastOvertime   time.Duration
+	overtimeCapped bool
+	
+	// Flowtime. workKind remembers whether the cycle is pomodoro or flow so
+	// that the session after a break continues it; pendingBreak is the break
+	// earned by the last flow session.
+	flowtimePolicy FlowtimePolicy
+	workKind       SessionType
+	lastFlowWork   time.Duration
+	pendingBreak   time.Duration
+	
+	// Extensions of the running session and of today as a whole.
+	extendPolicy    ExtendPolicy
+	extensions      int
+	extendedBy      time.Duration
+	extensionsToday int
+	extendDay       string
+	lastExtension   time.Duration
 }
 
 func NewSession() *Session {
+	ladder := DefaultWarningLadder()
+	
 	return &Session{
 		state:                Idle,
 		currentTimer:         NewTimer(0),
-		warningTimer:         NewTimer(WarningInterval),
+		warningTimer:         NewTimer(ladder[0].After),
 		sessionType:          Work,
+		workDuration:         WorkSessionDuration,
+		breakDuration:        BreakSessionDuration,
 		stateChangeCallbacks: make([]func(SessionState, SessionState), 0),
+		warningLadder:        ladder,
+		warningRepeat:        WarningInterval,
+		pausePolicy:          DefaultPausePolicy(),
+		pauseReminderTimer:   NewTimer(0),
+		skipPolicy:           DefaultSkipPolicy(),
+		skipDay:              dayKey(time.Now()),
+		autoStartPolicy:      DefaultAutoStartPolicy(),
+		autoStartTimer:       NewTimer(0),
+		overtimePolicy:       DefaultOvertimePolicy(),
+		overtimeTimer:        NewTimer(0),
+		flowtimePolicy:       DefaultFlowtimePolicy(),
+		workKind:             Work,
+		extendPolicy:         DefaultExtendPolicy(),
+		extendDay:            dayKey(time.Now()),
+	}
+}
+
+// ValidateDurations checks that work and break sessions have a length.
+func ValidateDurations(work, brk time.Duration) error {
+	if work <= 0 || brk <= 0 {
+		return ErrInvalidDuration
+	}
+	return nil
+}
+
+// SetDurations sets the length of future work and break sessions. A session
+// that is already running keeps its length.
+func (s *Session) SetDurations(work, brk time.Duration) error {
+	if err := ValidateDurations(work, brk); err != nil {
+		return NewSessionError("set durations", err)
+	}
+	
+	s.workDuration = work
+	s.breakDuration = brk
+	return nil
+}
+
+// GetDuration returns the configured length of a session of the given type.
+func (s *Session) GetDuration(sessionType SessionType) time.Duration {
+	if sessionType == Break {
+		return s.breakDuration
+	}
+	return s.workDuration
+}
+
+// SetWarningLadder replaces the idle-warning escalation. repeat is the interval
+// at which the final step is re-fired once the ladder has been climbed.
+func (s *Session) SetWarningLadder(steps []WarningStep, repeat time.Duration) error {
+	if err := ValidateWarningLadder(steps); err != nil {
+		return NewSessionError("set warning ladder", err)
+	}
+	if err := ValidateWarningRepeat(repeat); err != nil {
+		return NewSessionError("set warning ladder", err)
+	}
+	
+	s.warningLadder = append([]WarningStep(nil), steps...)
+	s.warningRepeat = repeat
+	return nil
+}
+
+// SetPausePolicy replaces the per-session pause budget. It takes effect for
+// the next pause; pauses already taken still count against it.
+func (s *Session) SetPausePolicy(policy PausePolicy) error {
+	if err := policy.Validate(); err != nil {
+		return NewSessionError("set pause policy", err)
+	}
+	
+	s.pausePolicy = policy
+	return nil
+}
+
+func (s *Session) GetPausePolicy() PausePolicy {
+	return s.pausePolicy
+}
+
+// SetSkipPolicy replaces the daily skip allowance.
+func (s *Session) SetSkipPolicy(policy SkipPolicy) error {
+	if err := policy.Validate(); err != nil {
+		return NewSessionError("set skip policy", err)
+	}
+	
+	s.skipPolicy = policy
+	return nil
+}
+
+func (s *Session) GetSkipPolicy() SkipPolicy {
+	return s.skipPolicy
+}
+
+// SetAutoStartPolicy replaces the auto-start settings. A countdown that is
+// already running is not affected.
+func (s *Session) SetAutoStartPolicy(policy AutoStartPolicy) error {
+	if err := policy.Validate(); err != nil {
+		return NewSessionError("set auto-start policy", err)
 	}
+	
+	s.autoStartPolicy = policy
+	return nil
+}
+
+func (s *Session) GetAutoStartPolicy() AutoStartPolicy {
+	return s.autoStartPolicy
+}
+
+// RestoreSkips seeds today's skip count, e.g. from persisted statistics.
+// Counts recorded for any other day are ignored.
+func (s *Session) RestoreSkips(day time.Time, count int) {
+	if dayKey(day) != dayKey(time.Now()) {
+		return
+	}
+	s.skipDay = dayKey(day)
+	s.skipsToday = count
+}
+
+// GetWarningLadder returns a copy of the configured escalation ladder.
+func (s *Session) GetWarningLadder() []WarningStep {
+	return append([]WarningStep(nil), s.warningLadder...)
 }
 
 func (s *Session) AddStateChangeCallback(callback func(SessionState, SessionState)) {
@@ -41,65 +214,323 @@ func (s *Session) StartWorkSession() error {
 		return nil
 	}
 	
+	if err := s.consumeSkip(); err != nil {
+		return err
+	}
+	
 	s.sessionType = Work
-	s.currentTimer.Reset(WorkSessionDuration)
+	s.workKind = Work
+	s.currentTimer.Reset(s.workDuration)
 	s.currentTimer.Start()
-	s.warningTimer.Stop()
+	s.stopWarnings()
+	s.resetPauseBudget()
+	s.resetExtensions()
+	s.CancelAutoStart()
+	s.resetOvertime()
 	s.setState(WorkSession)
 	
 	return nil
 }
 
 func (s *Session) StartBreakSession() error {
-	if s.state != Idle {
+	if s.state != Idle && s.state != Overtime {
 		return nil
 	}
 	
+	// 残業中なら残業を締めてから休憩へ
+	if s.state == Overtime {
+		s.finishOvertime()
+	}
+	
 	s.sessionType = Break
-	s.currentTimer.Reset(BreakSessionDuration)
+	s.breakDue = false
+	s.currentTimer.Reset(s.NextBreakDuration())
+	s.pendingBreak = 0
 	s.currentTimer.Start()
-	s.warningTimer.Stop()
+	s.stopWarnings()
+	s.resetPauseBudget()
+	s.resetExtensions()
+	s.CancelAutoStart()
 	s.setState(BreakSession)
 	
+	// 残業の上乗せはこの休憩で使い切る（統計は状態遷移の時点で残業時間を読む）
+	s.resetOvertime()
+	
 	return nil
 }
 
 func (s *Session) PauseSession() error {
-	if s.state != WorkSession && s.state != BreakSession {
+	if !s.isTimedSession() {
+		return nil
+	}
+	if s.currentTimer.IsPaused() {
 		return nil
 	}
+	if s.pausePolicy.Strict {
+		return NewSessionError("pause", ErrPauseDisabled)
+	}
+	if !s.CanPause() {
+		return NewSessionError("pause", ErrPauseBudgetExhausted)
+	}
 	
 	s.currentTimer.Pause()
+	s.pauseCount++
+	s.pauseStartedAt = time.Now()
+	if s.pausePolicy.ReminderInterval > 0 {
+		s.pauseReminderTimer.Reset(s.pausePolicy.ReminderInterval)
+		s.pauseReminderTimer.Start()
+	}
 	return nil
 }
 
 func (s *Session) ResumeSession() error {
-	if s.state != WorkSession && s.state != BreakSession {
+	if !s.isTimedSession() {
 		return nil
 	}
 	
+	s.endPause()
 	s.currentTimer.Resume()
 	return nil
 }
 
+// AbandonSession stops the running session without completing it. The session
+// becomes idle and the warning ladder starts as if it had ended normally.
+func (s *Session) AbandonSession() error {
+	if !s.isTimedSession() {
+		return nil
+	}
+	
+	s.endPause()
+	s.currentTimer.Stop()
+	s.abandoned = true
+	s.setState(Idle)
+	s.startWarnings()
+	return nil
+}
+
+// consumeSkip treats starting work while a break is due as a skip.
+func (s *Session) consumeSkip() error {
+	if !s.breakDue {
+		return nil
+	}
+	if !s.CanSkipBreak() {
+		return NewSessionError("skip break", ErrSkipLimitReached)
+	}
+	s.skipsToday++
+	s.breakDue = false
+	s.pendingBreak = 0
+	return nil
+}
+
+// SkipBreak skips a due break and starts the next work session, using up one
+// of today's skips.
+func (s *Session) SkipBreak() error {
+	if s.state != Idle || !s.breakDue {
+		return NewSessionError("skip break", ErrNoBreakDue)
+	}
+	if s.workKind == Flow {
+		return s.StartFlowSession()
+	}
+	return s.StartWorkSession()
+}
+
+// IsBreakDue reports whether a completed work session is waiting for its break.
+func (s *Session) IsBreakDue() bool {
+	return s.state == Idle && s.breakDue
+}
+
+// CanSkipBreak reports whether the due break may be skipped right now.
+func (s *Session) CanSkipBreak() bool {
+	if !s.IsBreakDue() {
+		return false
+	}
+	if s.SkipsRemaining() != 0 {
+		return true
+	}
+	return s.skipPolicy.Cooldown > 0 && s.SkipCooldownRemaining() == 0
+}
+
+// SkipsRemaining returns how many skips are left today, or -1 if unlimited.
+func (s *Session) SkipsRemaining() int {
+	if s.skipPolicy.IsUnlimited() {
+		return -1
+	}
+	s.rollSkipDay()
+	remaining := s.skipPolicy.DailyAllowance - s.skipsToday
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+// SkipsUsedToday returns how many breaks have been skipped today.
+func (s *Session) SkipsUsedToday() int {
+	s.rollSkipDay()
+	return s.skipsToday
+}
+
+// SkipCooldownRemaining returns how long the user must stay idle before a skip
+// beyond the daily allowance is permitted.
+func (s *Session) SkipCooldownRemaining() time.Duration {
+	if !s.IsBreakDue() || s.skipPolicy.Cooldown <= 0 {
+		return 0
+	}
+	remaining := s.skipPolicy.Cooldown - time.Since(s.idleSince)
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+func (s *Session) rollSkipDay() {
+	if today := dayKey(time.Now()); today != s.skipDay {
+		s.skipDay = today
+		s.skipsToday = 0
+	}
+}
+
+// IsAutoStartPending reports whether the next session will start on its own.
+func (s *Session) IsAutoStartPending() bool {
+	return s.state == Idle && s.autoStartPending
+}
+
+// ShouldAutoStart reports whether the auto-start countdown has run out.
+func (s *Session) ShouldAutoStart() bool {
+	return s.IsAutoStartPending() && s.autoStartTimer.IsFinished()
+}
+
+// AutoStartRemaining returns the time left on the auto-start countdown.
+func (s *Session) AutoStartRemaining() time.Duration {
+	if !s.IsAutoStartPending() {
+		return 0
+	}
+	return s.autoStartTimer.Remaining()
+}
+
+// NextSessionType returns the session type that follows the last one in the cycle.
+func (s *Session) NextSessionType() SessionType {
+	if s.sessionType == Break {
+		return s.workKind
+	}
+	return s.sessionType.Next()
+}
+
+// CancelAutoStart stops a pending auto-start countdown.
+func (s *Session) CancelAutoStart() {
+	s.autoStartPending = false
+	s.autoStartTimer.Stop()
+}
+
+func (s *Session) scheduleAutoStart() {
+	if !s.autoStartPolicy.Enabled {
+		return
+	}
+	s.autoStartPending = true
+	s.autoStartTimer.Reset(s.autoStartPolicy.Delay)
+	s.autoStartTimer.Start()
+}
+
+// CanPause reports whether the running session may be paused right now.
+func (s *Session) CanPause() bool {
+	if !s.isTimedSession() {
+		return false
+	}
+	if s.currentTimer.IsPaused() || s.pausePolicy.Strict {
+		return false
+	}
+	if s.pausePolicy.MaxPauses > 0 && s.pauseCount >= s.pausePolicy.MaxPauses {
+		return false
+	}
+	if s.pausePolicy.MaxPauseTime > 0 && s.pausedTotal >= s.pausePolicy.MaxPauseTime {
+		return false
+	}
+	return true
+}
+
+// PausesUsed returns how many times the current session has been paused.
+func (s *Session) PausesUsed() int {
+	return s.pauseCount
+}
+
+// PauseTimeUsed returns the total time the current session has spent paused,
+// including an ongoing pause.
+func (s *Session) PauseTimeUsed() time.Duration {
+	if s.currentTimer.IsPaused() {
+		return s.pausedTotal + time.Since(s.pauseStartedAt)
+	}
+	return s.pausedTotal
+}
+
+// IsPauseBudgetExhausted reports whether an ongoing pause has used up the
+// session's pause-time allowance.
+func (s *Session) IsPauseBudgetExhausted() bool {
+	if !s.currentTimer.IsPaused() || s.pausePolicy.MaxPauseTime <= 0 {
+		return false
+	}
+	return s.PauseTimeUsed() >= s.pausePolicy.MaxPauseTime
+}
+
+// ShouldRemindPause reports whether the paused-too-long reminder is due.
+func (s *Session) ShouldRemindPause() bool {
+	if !s.currentTimer.IsPaused() || s.pausePolicy.ReminderInterval <= 0 {
+		return false
+	}
+	return s.pauseReminderTimer.IsFinished()
+}
+
+func (s *Session) ResetPauseReminder() {
+	s.pauseReminderTimer.Reset(s.pausePolicy.ReminderInterval)
+	s.pauseReminderTimer.Start()
+}
+
+// WasAbandoned reports whether the last session was abandoned rather than completed.
+func (s *Session) WasAbandoned() bool {
+	return s.abandoned
+}
+
+func (s *Session) endPause() {
+	if !s.currentTimer.IsPaused() {
+		return
+	}
+	s.pausedTotal += time.Since(s.pauseStartedAt)
+	s.pauseReminderTimer.Stop()
+}
+
+func (s *Session) resetPauseBudget() {
+	s.pauseCount = 0
+	s.pausedTotal = 0
+	s.abandoned = false
+	s.pauseReminderTimer.Stop()
+}
+
 func (s *Session) Update() {
 	s.currentTimer.Update()
 	
 	if s.currentTimer.IsFinished() {
 		switch s.state {
 		case WorkSession:
+			s.breakDue = true
+			s.scheduleAutoStart()
 			s.setState(Idle)
-			s.warningTimer.Reset(WarningInterval)
-			s.warningTimer.Start()
+			s.startWarnings()
 		case BreakSession:
+			s.scheduleAutoStart()
 			s.setState(Idle)
-			s.warningTimer.Reset(WarningInterval)
-			s.warningTimer.Start()
+			s.startWarnings()
 		}
 	}
 	
 	if s.state == Idle {
 		s.warningTimer.Update()
+		s.autoStartTimer.Update()
+	}
+	
+	if s.state == Overtime {
+		s.updateOvertime()
+	}
+	
+	if s.currentTimer.IsPaused() {
+		s.pauseReminderTimer.Update()
 	}
 }
 
@@ -111,11 +542,45 @@ func (s *Session) ShouldShowWarning() bool {
 	return s.warningTimer.IsFinished()
 }
 
+// ResetWarningTimer acknowledges the current warning and arms the timer for
+// the next step of the ladder.
 func (s *Session) ResetWarningTimer() {
-	s.warningTimer.Reset(WarningInterval)
+	s.warningStep++
+	s.warningTimer.Reset(s.nextWarningDelay())
 	s.warningTimer.Start()
 }
 
+// CurrentWarningStep returns the ladder step that fires when the warning timer
+// expires. Past the end of the ladder the final step is returned.
+func (s *Session) CurrentWarningStep() WarningStep {
+	if s.warningStep >= len(s.warningLadder) {
+		return s.warningLadder[len(s.warningLadder)-1]
+	}
+	return s.warningLadder[s.warningStep]
+}
+
+func (s *Session) startWarnings() {
+	s.idleSince = time.Now()
+	s.warningStep = 0
+	s.warningTimer.Reset(s.nextWarningDelay())
+	s.warningTimer.Start()
+}
+
+func (s *Session) stopWarnings() {
+	s.warningStep = 0
+	s.warningTimer.Stop()
+}
+
+func (s *Session) nextWarningDelay() time.Duration {
+	if s.warningStep >= len(s.warningLadder) {
+		return s.warningRepeat
+	}
+	if s.warningStep == 0 {
+		return s.warningLadder[0].After
+	}
+	return s.warningLadder[s.warningStep].After - s.warningLadder[s.warningStep-1].After
+}
+
 func (s *Session) GetState() SessionState {
 	return s.state
 }
@@ -133,7 +598,12 @@ func (s *Session) GetWarningTimer() *Timer {
 }
 
 func (s *Session) IsSessionActive() bool {
-	return s.state == WorkSession || s.state == BreakSession
+	return s.isTimedSession() || s.state == Overtime
+}
+
+// isTimedSession reports whether a session that can be paused or abandoned is running.
+func (s *Session) isTimedSession() bool {
+	return s.state == WorkSession || s.state == BreakSession || s.state == FlowSession
 }
 
 func (s *Session) IsSessionPaused() bool {
@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"errors"
 	"testing"
 	"time"
 )
@@ -328,4 +329,632 @@ func TestSessionState_String(t *testing.T) {
 	if Idle.String() != "Idle" {
 		t.Errorf("Expected Idle.String() to be 'Idle', got %v", Idle.String())
 	}
-}
\ No newline at end of file
+}
+
+func TestSession_WarningLadderEscalates(t *testing.T) {
+	session := NewSession()
+	err := session.SetWarningLadder([]WarningStep{
+		{After: 20 * time.Millisecond, Severity: WarningGentle},
+		{After: 40 * time.Millisecond, Severity: WarningUrgent},
+	}, 30*time.Millisecond)
+	if err != nil {
+		t.Fatalf("SetWarningLadder should not return error, got %v", err)
+	}
+	
+	session.setState(Idle)
+	session.startWarnings()
+	
+	expected := []WarningSeverity{WarningGentle, WarningUrgent, WarningUrgent}
+	for i, severity := range expected {
+		time.Sleep(50 * time.Millisecond)
+		session.Update()
+		
+		if !session.ShouldShowWarning() {
+			t.Fatalf("Expected warning %d to be due", i)
+		}
+		if got := session.CurrentWarningStep().Severity; got != severity {
+			t.Errorf("Expected warning %d to be %v, got %v", i, severity, got)
+		}
+		session.ResetWarningTimer()
+	}
+}
+
+func TestSession_WarningLadderResetsOnStart(t *testing.T) {
+	session := NewSession()
+	session.setState(Idle)
+	session.startWarnings()
+	session.ResetWarningTimer()
+	session.ResetWarningTimer()
+	
+	session.StartWorkSession()
+	
+	if session.CurrentWarningStep() != DefaultWarningLadder()[0] {
+		t.Errorf("Expected ladder to restart at first step, got %+v", session.CurrentWarningStep())
+	}
+}
+
+func TestSession_SetWarningLadderValidation(t *testing.T) {
+	session := NewSession()
+	
+	if err := session.SetWarningLadder(nil, time.Minute); err == nil {
+		t.Error("Empty ladder should be rejected")
+	}
+	
+	unordered := []WarningStep{
+		{After: 5 * time.Minute, Severity: WarningNotice},
+		{After: 2 * time.Minute, Severity: WarningGentle},
+	}
+	if err := session.SetWarningLadder(unordered, time.Minute); err == nil {
+		t.Error("Unordered ladder should be rejected")
+	}
+	
+	if len(session.GetWarningLadder()) != len(DefaultWarningLadder()) {
+		t.Error("Rejected ladder should leave the previous ladder in place")
+	}
+}
+
+func TestWarningSeverity_TextRoundTrip(t *testing.T) {
+	for severity := WarningGentle; severity <= WarningCritical; severity++ {
+		text, err := severity.MarshalText()
+		if err != nil {
+			t.Fatalf("MarshalText(%v) returned error %v", severity, err)
+		}
+		
+		var decoded WarningSeverity
+		if err := decoded.UnmarshalText(text); err != nil {
+			t.Fatalf("UnmarshalText(%q) returned error %v", text, err)
+		}
+		if decoded != severity {
+			t.Errorf("Expected %v after round trip, got %v", severity, decoded)
+		}
+	}
+}
+
+func TestSession_StrictModeDisablesPause(t *testing.T) {
+	session := NewSession()
+	session.SetPausePolicy(PausePolicy{Strict: true})
+	session.StartWorkSession()
+	
+	if session.CanPause() {
+		t.Error("CanPause should be false in strict mode")
+	}
+	
+	err := session.PauseSession()
+	if !errors.Is(err, ErrPauseDisabled) {
+		t.Errorf("Expected ErrPauseDisabled, got %v", err)
+	}
+	if session.IsSessionPaused() {
+		t.Error("Session should not be paused in strict mode")
+	}
+}
+
+func TestSession_PausesUnlimitedByDefault(t *testing.T) {
+	session := NewSession()
+	session.StartWorkSession()
+	
+	for i := 0; i < 10; i++ {
+		if err := session.PauseSession(); err != nil {
+			t.Fatalf("Pause %d should succeed without a configured budget, got %v", i+1, err)
+		}
+		session.ResumeSession()
+	}
+	if session.ShouldRemindPause() {
+		t.Error("No reminders should be due without a configured interval")
+	}
+}
+
+func TestSession_PauseCountLimit(t *testing.T) {
+	session := NewSession()
+	session.SetPausePolicy(PausePolicy{MaxPauses: 1})
+	session.StartWorkSession()
+	
+	if err := session.PauseSession(); err != nil {
+		t.Fatalf("First pause should succeed, got %v", err)
+	}
+	session.ResumeSession()
+	
+	err := session.PauseSession()
+	if !errors.Is(err, ErrPauseBudgetExhausted) {
+		t.Errorf("Expected ErrPauseBudgetExhausted, got %v", err)
+	}
+	if session.PausesUsed() != 1 {
+		t.Errorf("Expected 1 pause used, got %d", session.PausesUsed())
+	}
+	
+	// A new session gets a fresh budget
+	session.AbandonSession()
+	session.StartBreakSession()
+	if !session.CanPause() {
+		t.Error("New session should be allowed to pause")
+	}
+}
+
+func TestSession_PauseTimeBudget(t *testing.T) {
+	session := NewSession()
+	session.SetPausePolicy(PausePolicy{MaxPauseTime: 50 * time.Millisecond, ReminderInterval: 20 * time.Millisecond})
+	session.StartWorkSession()
+	session.PauseSession()
+	
+	time.Sleep(30 * time.Millisecond)
+	session.Update()
+	if !session.ShouldRemindPause() {
+		t.Error("Pause reminder should be due")
+	}
+	if session.IsPauseBudgetExhausted() {
+		t.Error("Pause budget should not be exhausted yet")
+	}
+	
+	time.Sleep(30 * time.Millisecond)
+	if !session.IsPauseBudgetExhausted() {
+		t.Error("Pause budget should be exhausted")
+	}
+}
+
+func TestSession_AbandonSession(t *testing.T) {
+	session := NewSession()
+	
+	var newState SessionState
+	session.AddStateChangeCallback(func(old, new SessionState) {
+		newState = new
+	})
+	
+	session.StartWorkSession()
+	session.PauseSession()
+	if err := session.AbandonSession(); err != nil {
+		t.Errorf("AbandonSession should not return error, got %v", err)
+	}
+	
+	if newState != Idle || session.GetState() != Idle {
+		t.Errorf("Expected state to be Idle after abandon, got %v", session.GetState())
+	}
+	if !session.WasAbandoned() {
+		t.Error("WasAbandoned should be true after abandon")
+	}
+	if session.IsSessionPaused() {
+		t.Error("Abandoned session should not remain paused")
+	}
+	
+	session.StartWorkSession()
+	if session.WasAbandoned() {
+		t.Error("WasAbandoned should be cleared when a session starts")
+	}
+}
+
+// completeWorkSession drives the session through a very short work session.
+func completeWorkSession(t *testing.T, session *Session) {
+	t.Helper()
+	session.StartWorkSession()
+	session.currentTimer.Reset(10 * time.Millisecond)
+	session.currentTimer.Start()
+	time.Sleep(20 * time.Millisecond)
+	session.Update()
+	
+	if !session.IsBreakDue() {
+		t.Fatal("A break should be due after completing work")
+	}
+}
+
+func TestSession_SkipBreakAllowance(t *testing.T) {
+	session := NewSession()
+	session.SetSkipPolicy(SkipPolicy{DailyAllowance: 1})
+	
+	completeWorkSession(t, session)
+	if session.SkipsRemaining() != 1 {
+		t.Errorf("Expected 1 skip remaining, got %d", session.SkipsRemaining())
+	}
+	if err := session.SkipBreak(); err != nil {
+		t.Fatalf("First skip should succeed, got %v", err)
+	}
+	if session.GetState() != WorkSession {
+		t.Errorf("Expected WorkSession after skip, got %v", session.GetState())
+	}
+	
+	session.AbandonSession()
+	if session.IsBreakDue() {
+		t.Error("Abandoning work should not make a break due")
+	}
+	
+	completeWorkSession(t, session)
+	if session.CanSkipBreak() {
+		t.Error("Skip should not be allowed once the allowance is used")
+	}
+	err := session.StartWorkSession()
+	if !errors.Is(err, ErrSkipLimitReached) {
+		t.Errorf("Expected ErrSkipLimitReached, got %v", err)
+	}
+	if session.GetState() != Idle {
+		t.Errorf("Expected to remain Idle, got %v", session.GetState())
+	}
+	
+	if err := session.StartBreakSession(); err != nil {
+		t.Errorf("Taking the break should always be allowed, got %v", err)
+	}
+	if session.IsBreakDue() {
+		t.Error("Break should no longer be due once it has started")
+	}
+}
+
+func TestSession_SkipsUnlimitedByDefault(t *testing.T) {
+	session := NewSession()
+	
+	for i := 0; i < 3; i++ {
+		completeWorkSession(t, session)
+		if err := session.SkipBreak(); err != nil {
+			t.Fatalf("Skip %d should succeed without a configured allowance, got %v", i+1, err)
+		}
+		session.AbandonSession()
+	}
+}
+
+func TestSession_SkipBreakCooldown(t *testing.T) {
+	session := NewSession()
+	session.SetSkipPolicy(SkipPolicy{DailyAllowance: 0, Cooldown: 30 * time.Millisecond})
+	
+	completeWorkSession(t, session)
+	if session.CanSkipBreak() {
+		t.Error("Skip should wait for the cooldown")
+	}
+	
+	time.Sleep(40 * time.Millisecond)
+	if !session.CanSkipBreak() {
+		t.Error("Skip should be allowed after the cooldown")
+	}
+}
+
+func TestSession_SkipBreakRequiresDueBreak(t *testing.T) {
+	session := NewSession()
+	
+	if err := session.SkipBreak(); !errors.Is(err, ErrNoBreakDue) {
+		t.Errorf("Expected ErrNoBreakDue, got %v", err)
+	}
+}
+
+func TestSession_RestoreSkips(t *testing.T) {
+	session := NewSession()
+	session.SetSkipPolicy(SkipPolicy{DailyAllowance: 3})
+	
+	session.RestoreSkips(time.Now().AddDate(0, 0, -1), 3)
+	if session.SkipsUsedToday() != 0 {
+		t.Errorf("Skips from another day should be ignored, got %d", session.SkipsUsedToday())
+	}
+	
+	session.RestoreSkips(time.Now(), 2)
+	if session.SkipsRemaining() != 1 {
+		t.Errorf("Expected 1 skip remaining, got %d", session.SkipsRemaining())
+	}
+}
+
+func TestSession_AutoStartSchedulesNextSession(t *testing.T) {
+	session := NewSession()
+	session.SetAutoStartPolicy(AutoStartPolicy{Enabled: true, Delay: 30 * time.Millisecond})
+	
+	completeWorkSession(t, session)
+	if !session.IsAutoStartPending() {
+		t.Fatal("Auto-start should be pending after a session ends")
+	}
+	if session.NextSessionType() != Break {
+		t.Errorf("Expected next session to be Break, got %v", session.NextSessionType())
+	}
+	if session.ShouldAutoStart() {
+		t.Error("Auto-start should wait for its countdown")
+	}
+	
+	time.Sleep(40 * time.Millisecond)
+	session.Update()
+	if !session.ShouldAutoStart() {
+		t.Error("Auto-start should be due after the countdown")
+	}
+	
+	session.StartBreakSession()
+	if session.IsAutoStartPending() {
+		t.Error("Starting a session should clear the pending auto-start")
+	}
+}
+
+func TestSession_AutoStartDisabledByDefault(t *testing.T) {
+	session := NewSession()
+	
+	completeWorkSession(t, session)
+	if session.IsAutoStartPending() {
+		t.Error("Auto-start should be disabled by default")
+	}
+}
+
+func TestSession_CancelAutoStart(t *testing.T) {
+	session := NewSession()
+	session.SetAutoStartPolicy(AutoStartPolicy{Enabled: true, Delay: 10 * time.Millisecond})
+	
+	completeWorkSession(t, session)
+	session.CancelAutoStart()
+	
+	time.Sleep(20 * time.Millisecond)
+	session.Update()
+	if session.ShouldAutoStart() {
+		t.Error("Cancelled auto-start should not fire")
+	}
+}
+
+func TestSession_OvertimeContinuesAndCaps(t *testing.T) {
+	session := NewSession()
+	session.SetOvertimePolicy(OvertimePolicy{Enabled: true, MaxOvertime: 40 * time.Millisecond})
+	
+	completeWorkSession(t, session)
+	if err := session.ContinueOvertime(); err != nil {
+		t.Fatalf("ContinueOvertime should not return error, got %v", err)
+	}
+	if session.GetState() != Overtime {
+		t.Fatalf("Expected Overtime state, got %v", session.GetState())
+	}
+	
+	time.Sleep(20 * time.Millisecond)
+	session.Update()
+	if session.OvertimeElapsed() < 15*time.Millisecond {
+		t.Errorf("Overtime should count up, got %v", session.OvertimeElapsed())
+	}
+	
+	time.Sleep(30 * time.Millisecond)
+	session.Update()
+	if session.GetState() != Idle {
+		t.Fatalf("Expected Idle after the overtime cap, got %v", session.GetState())
+	}
+	if !session.WasOvertimeCapped() || session.LastOvertime() != 40*time.Millisecond {
+		t.Errorf("Expected capped overtime of 40ms, got %v", session.LastOvertime())
+	}
+	if session.CanContinueOvertime() {
+		t.Error("Overtime should not be allowed again once capped")
+	}
+	if !session.IsBreakDue() {
+		t.Error("Break should still be due after overtime")
+	}
+}
+
+func TestSession_OvertimeScalesNextBreak(t *testing.T) {
+	session := NewSession()
+	session.SetDurations(time.Minute, time.Minute)
+	session.SetOvertimePolicy(OvertimePolicy{Enabled: true, MaxOvertime: time.Hour, BreakScale: 0.5})
+	
+	completeWorkSession(t, session)
+	session.ContinueOvertime()
+	time.Sleep(40 * time.Millisecond)
+	
+	// 統計と同じく状態遷移の時点で残業時間を読む
+	var overtime time.Duration
+	session.AddStateChangeCallback(func(old, new SessionState) {
+		if new == BreakSession {
+			overtime = session.LastOvertime()
+		}
+	})
+	if err := session.StartBreakSession(); err != nil {
+		t.Fatalf("StartBreakSession from overtime should not return error, got %v", err)
+	}
+	if session.GetState() != BreakSession {
+		t.Fatalf("Expected BreakSession, got %v", session.GetState())
+	}
+	
+	if overtime < 40*time.Millisecond {
+		t.Errorf("Expected overtime of at least 40ms, got %v", overtime)
+	}
+	expected := time.Minute + overtime/2
+	if session.currentTimer.duration != expected {
+		t.Errorf("Expected break of %v, got %v", expected, session.currentTimer.duration)
+	}
+}
+
+func TestSession_OvertimeBonusOnlyOnce(t *testing.T) {
+	session := NewSession()
+	session.SetDurations(time.Minute, time.Minute)
+	session.SetOvertimePolicy(OvertimePolicy{Enabled: true, MaxOvertime: time.Hour, BreakScale: 1})
+	
+	completeWorkSession(t, session)
+	session.ContinueOvertime()
+	time.Sleep(40 * time.Millisecond)
+	session.StartBreakSession()
+	if session.currentTimer.duration <= time.Minute {
+		t.Fatalf("Expected the first break to include the overtime, got %v", session.currentTimer.duration)
+	}
+	
+	// 休憩を終えてからもう一度休憩を始める
+	session.currentTimer.Reset(10 * time.Millisecond)
+	session.currentTimer.Start()
+	time.Sleep(20 * time.Millisecond)
+	session.Update()
+	if session.GetState() != Idle {
+		t.Fatalf("Expected Idle after the break, got %v", session.GetState())
+	}
+	
+	session.StartBreakSession()
+	if session.currentTimer.duration != time.Minute {
+		t.Errorf("Expected a plain break the second time, got %v", session.currentTimer.duration)
+	}
+	if session.NextBreakDuration() != time.Minute {
+		t.Errorf("Expected no bonus left, got %v", session.NextBreakDuration())
+	}
+}
+
+func TestSession_OvertimeDisabled(t *testing.T) {
+	session := NewSession()
+	session.SetOvertimePolicy(OvertimePolicy{})
+	
+	completeWorkSession(t, session)
+	if err := session.ContinueOvertime(); err == nil {
+		t.Error("ContinueOvertime should fail when overtime is disabled")
+	}
+}
+
+func TestSessionState_OvertimeString(t *testing.T) {
+	if Overtime.String() != "Overtime" {
+		t.Errorf("Expected Overtime.String() to be 'Overtime', got %v", Overtime.String())
+	}
+}
+
+func TestFlowtimePolicy_BreakFor(t *testing.T) {
+	policy := DefaultFlowtimePolicy()
+	
+	cases := []struct {
+		worked   time.Duration
+		expected time.Duration
+	}{
+		{10 * time.Minute, 5 * time.Minute},
+		{30 * time.Minute, 8 * time.Minute},
+		{60 * time.Minute, 10 * time.Minute},
+		{120 * time.Minute, 15 * time.Minute},
+	}
+	for _, c := range cases {
+		if got := policy.BreakFor(c.worked); got != c.expected {
+			t.Errorf("BreakFor(%v): expected %v, got %v", c.worked, c.expected, got)
+		}
+	}
+	
+	fraction := FlowtimePolicy{BreakFraction: 0.2, MinBreak: 2 * time.Minute}
+	if got := fraction.BreakFor(50 * time.Minute); got != 10*time.Minute {
+		t.Errorf("Expected 10m from the fraction, got %v", got)
+	}
+	if got := fraction.BreakFor(time.Minute); got != 2*time.Minute {
+		t.Errorf("Expected the minimum break, got %v", got)
+	}
+}
+
+func TestFlowtimePolicy_Validate(t *testing.T) {
+	if err := DefaultFlowtimePolicy().Validate(); err != nil {
+		t.Errorf("Default flowtime policy should be valid, got %v", err)
+	}
+	
+	unordered := FlowtimePolicy{BreakTable: []FlowBreakStep{
+		{UpTo: 50 * time.Minute, Break: 8 * time.Minute},
+		{UpTo: 25 * time.Minute, Break: 5 * time.Minute},
+	}}
+	if err := unordered.Validate(); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("Expected ErrInvalidConfig for an unordered table, got %v", err)
+	}
+	
+	openEndedFirst := FlowtimePolicy{BreakTable: []FlowBreakStep{
+		{Break: 15 * time.Minute},
+		{UpTo: 25 * time.Minute, Break: 5 * time.Minute},
+	}}
+	if err := openEndedFirst.Validate(); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("Expected ErrInvalidConfig when the open-ended step is not last, got %v", err)
+	}
+}
+
+func TestSession_FlowSessionEarnsBreak(t *testing.T) {
+	session := NewSession()
+	session.SetFlowtimePolicy(FlowtimePolicy{BreakFraction: 0.5, MinBreak: 3 * time.Minute})
+	
+	if err := session.StartFlowSession(); err != nil {
+		t.Fatalf("StartFlowSession should not return error, got %v", err)
+	}
+	if session.GetState() != FlowSession || session.GetSessionType() != Flow {
+		t.Fatalf("Expected FlowSession/Flow, got %v/%v", session.GetState(), session.GetSessionType())
+	}
+	
+	time.Sleep(40 * time.Millisecond)
+	session.Update()
+	if session.GetState() != FlowSession {
+		t.Fatalf("Flow session should not finish on its own, got %v", session.GetState())
+	}
+	if session.FlowElapsed() < 40*time.Millisecond {
+		t.Errorf("Flow session should count up, got %v", session.FlowElapsed())
+	}
+	
+	if err := session.StopFlowSession(); err != nil {
+		t.Fatalf("StopFlowSession should not return error, got %v", err)
+	}
+	if session.GetState() != Idle || !session.IsBreakDue() {
+		t.Fatalf("Expected Idle with a break due, got %v", session.GetState())
+	}
+	
+	if session.LastFlowWork() < 40*time.Millisecond {
+		t.Errorf("Expected the flow work to be recorded, got %v", session.LastFlowWork())
+	}
+	if session.NextBreakDuration() != 3*time.Minute {
+		t.Errorf("Expected earned break of 3m, got %v", session.NextBreakDuration())
+	}
+}
+
+func TestSession_FlowBreakUsedByBreakSession(t *testing.T) {
+	session := NewSession()
+	
+	session.StartFlowSession()
+	session.StopFlowSession()
+	
+	if err := session.StartBreakSession(); err != nil {
+		t.Fatalf("StartBreakSession should not return error, got %v", err)
+	}
+	remaining := session.GetTimeRemaining()
+	if remaining > 5*time.Minute || remaining < 5*time.Minute-time.Second {
+		t.Errorf("Expected the 5 minute flow break, got %v", remaining)
+	}
+	if session.NextSessionType() != Flow {
+		t.Errorf("Expected the cycle to return to Flow, got %v", session.NextSessionType())
+	}
+}
+
+func TestSession_ExtendSession(t *testing.T) {
+	session := NewSession()
+	
+	if err := session.ExtendSession(time.Minute); !errors.Is(err, ErrInvalidState) {
+		t.Errorf("Expected ErrInvalidState when idle, got %v", err)
+	}
+	
+	session.StartWorkSession()
+	before := session.GetTimeRemaining()
+	if err := session.ExtendSession(2 * time.Minute); err != nil {
+		t.Fatalf("ExtendSession should not return error, got %v", err)
+	}
+	if session.GetTimeRemaining() <= before+time.Minute {
+		t.Errorf("Expected remaining time to grow by 2m, got %v", session.GetTimeRemaining())
+	}
+	if session.Extensions() != 1 || session.ExtendedBy() != 2*time.Minute {
+		t.Errorf("Expected 1 extension of 2m, got %d/%v", session.Extensions(), session.ExtendedBy())
+	}
+	
+	if err := session.ExtendSession(time.Hour); !errors.Is(err, ErrInvalidDuration) {
+		t.Errorf("Expected ErrInvalidDuration beyond the max extension, got %v", err)
+	}
+}
+
+func TestSession_ExtendLimits(t *testing.T) {
+	session := NewSession()
+	session.SetExtendPolicy(ExtendPolicy{Step: time.Minute, MaxPerSession: 1, MaxPerDay: 2})
+	
+	session.StartWorkSession()
+	session.ExtendSession(time.Minute)
+	if session.CanExtend() {
+		t.Error("Per-session limit should stop a second extension")
+	}
+	if err := session.ExtendSession(time.Minute); !errors.Is(err, ErrExtendLimitReached) {
+		t.Errorf("Expected ErrExtendLimitReached, got %v", err)
+	}
+	
+	session.AbandonSession()
+	session.StartWorkSession()
+	if session.ExtensionsRemaining() != 1 {
+		t.Errorf("Expected 1 extension left today, got %d", session.ExtensionsRemaining())
+	}
+	session.ExtendSession(time.Minute)
+	
+	session.AbandonSession()
+	session.StartWorkSession()
+	if session.CanExtend() {
+		t.Error("Per-day limit should stop further extensions")
+	}
+	if session.ExtensionsToday() != 2 {
+		t.Errorf("Expected 2 extensions today, got %d", session.ExtensionsToday())
+	}
+}
+
+func TestSession_RestoreExtensions(t *testing.T) {
+	session := NewSession()
+	session.SetExtendPolicy(ExtendPolicy{Step: time.Minute, MaxPerDay: 3})
+	
+	session.RestoreExtensions(time.Now().AddDate(0, 0, -1), 3)
+	session.StartWorkSession()
+	if !session.CanExtend() {
+		t.Error("Extensions from another day should be ignored")
+	}
+	
+	session.RestoreExtensions(time.Now(), 3)
+	if session.CanExtend() {
+		t.Error("Today's restored extensions should count against the limit")
+	}
+}
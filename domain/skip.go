@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// SkipPolicy limits how often a due break may be skipped per day. Once the
+// allowance is used up a skip is refused, or, if Cooldown is set, only allowed
+// after the user has sat idle for the cooldown. A negative allowance means unlimited.
+type SkipPolicy struct {
+	DailyAllowance int           `json:"daily_allowance"`
+	Cooldown       time.Duration `json:"cooldown"`
+}
+
+// DefaultSkipPolicy returns the skip allowance used when nothing is configured:
+// unlimited, as before the allowance existed. A daily limit is opt-in.
+func DefaultSkipPolicy() SkipPolicy {
+	return SkipPolicy{
+		DailyAllowance: -1,
+		Cooldown:       0,
+	}
+}
+
+// Validate checks that the cooldown is not negative.
+func (p SkipPolicy) Validate() error {
+	if p.Cooldown < 0 {
+		return fmt.Errorf("%w: skip cooldown must not be negative", ErrInvalidConfig)
+	}
+	return nil
+}
+
+// IsUnlimited reports whether breaks may always be skipped.
+func (p SkipPolicy) IsUnlimited() bool {
+	return p.DailyAllowance < 0
+}
+
+// dayKey identifies the calendar day used for daily allowances.
+func dayKey(t time.Time) string {
+	return t.Format("2006-01-02")
+}
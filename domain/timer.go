@@ -11,6 +11,11 @@ type Timer struct {
 	isPaused    bool
 	startTime   time.Time
 	pausedTime  time.Time
+	
+	// countUp timers have no end; elapsed accumulates instead of remaining
+	// shrinking.
+	countUp     bool
+	elapsed     time.Duration
 }
 
 func NewTimer(duration time.Duration) *Timer {
@@ -22,6 +27,27 @@ func NewTimer(duration time.Duration) *Timer {
 	}
 }
 
+// NewStopwatch creates a timer that counts up from zero with no fixed end.
+func NewStopwatch() *Timer {
+	return &Timer{countUp: true}
+}
+
+// StartCountUp switches the timer to count-up mode and starts it from zero.
+func (t *Timer) StartCountUp() {
+	t.duration = 0
+	t.remaining = 0
+	t.countUp = true
+	t.elapsed = 0
+	t.isRunning = true
+	t.isPaused = false
+	t.startTime = time.Now()
+}
+
+// IsCountUp reports whether the timer counts up rather than down.
+func (t *Timer) IsCountUp() bool {
+	return t.countUp
+}
+
 func (t *Timer) Start() {
 	if t.isPaused {
 		t.Resume()
@@ -40,6 +66,10 @@ func (t *Timer) Pause() {
 	
 	t.isPaused = true
 	t.pausedTime = time.Now()
+	if t.countUp {
+		t.elapsed += time.Since(t.startTime)
+		return
+	}
 	elapsed := time.Since(t.startTime)
 	t.remaining = t.remaining - elapsed
 	
@@ -61,6 +91,7 @@ func (t *Timer) Stop() {
 	t.isRunning = false
 	t.isPaused = false
 	t.remaining = t.duration
+	t.elapsed = 0
 }
 
 func (t *Timer) Reset(duration time.Duration) {
@@ -68,6 +99,25 @@ func (t *Timer) Reset(duration time.Duration) {
 	t.remaining = duration
 	t.isRunning = false
 	t.isPaused = false
+	t.countUp = false
+	t.elapsed = 0
+}
+
+// Extend adds d to a running or paused countdown timer. Count-up timers are
+// not affected.
+func (t *Timer) Extend(d time.Duration) {
+	if t.countUp || d <= 0 {
+		return
+	}
+	
+	t.duration += d
+	if t.isRunning && !t.isPaused {
+		// 経過分を確定させてから延長する
+		t.remaining = t.Remaining() + d
+		t.startTime = time.Now()
+		return
+	}
+	t.remaining += d
 }
 
 func (t *Timer) Update() {
@@ -75,6 +125,12 @@ func (t *Timer) Update() {
 		return
 	}
 	
+	if t.countUp {
+		t.elapsed += time.Since(t.startTime)
+		t.startTime = time.Now()
+		return
+	}
+	
 	elapsed := time.Since(t.startTime)
 	newRemaining := t.remaining - elapsed
 	
@@ -89,6 +145,9 @@ func (t *Timer) Update() {
 }
 
 func (t *Timer) IsFinished() bool {
+	if t.countUp {
+		return false
+	}
 	return t.remaining <= 0 && !t.isPaused
 }
 
@@ -101,6 +160,10 @@ func (t *Timer) IsPaused() bool {
 }
 
 func (t *Timer) Remaining() time.Duration {
+	if t.countUp {
+		return 0
+	}
+	
 	if t.isPaused {
 		return t.remaining
 	}
@@ -119,7 +182,22 @@ func (t *Timer) Remaining() time.Duration {
 	return remaining
 }
 
+// Elapsed returns how much of the timer's duration has been used.
+func (t *Timer) Elapsed() time.Duration {
+	if t.countUp {
+		if t.isRunning && !t.isPaused {
+			return t.elapsed + time.Since(t.startTime)
+		}
+		return t.elapsed
+	}
+	return t.duration - t.Remaining()
+}
+
 func (t *Timer) Progress() float64 {
+	if t.countUp {
+		return 0
+	}
+	
 	if t.duration == 0 {
 		return 1.0
 	}
@@ -81,4 +81,62 @@ func TestTimer_Finish(t *testing.T) {
 	if timer.Remaining() != 0 {
 		t.Errorf("Expected 0 remaining time, got %v", timer.Remaining())
 	}
-}
\ No newline at end of file
+}
+
+func TestTimer_CountUp(t *testing.T) {
+	timer := NewStopwatch()
+	timer.StartCountUp()
+	
+	time.Sleep(100 * time.Millisecond)
+	timer.Update()
+	
+	if timer.IsFinished() {
+		t.Error("Count-up timer should never finish")
+	}
+	
+	elapsed := timer.Elapsed()
+	if elapsed < 100*time.Millisecond || elapsed > 200*time.Millisecond {
+		t.Errorf("Expected elapsed time around 100ms, got %v", elapsed)
+	}
+	
+	timer.Pause()
+	paused := timer.Elapsed()
+	time.Sleep(50 * time.Millisecond)
+	if timer.Elapsed() != paused {
+		t.Errorf("Count-up timer continued while paused. Expected %v, got %v", paused, timer.Elapsed())
+	}
+	
+	timer.Resume()
+	time.Sleep(50 * time.Millisecond)
+	if timer.Elapsed() < paused+50*time.Millisecond {
+		t.Errorf("Expected elapsed time to grow after resume, got %v", timer.Elapsed())
+	}
+	
+	timer.Reset(time.Second)
+	if timer.IsCountUp() {
+		t.Error("Reset should return the timer to countdown mode")
+	}
+}
+
+func TestTimer_Extend(t *testing.T) {
+	timer := NewTimer(100 * time.Millisecond)
+	timer.Start()
+	
+	timer.Extend(time.Second)
+	remaining := timer.Remaining()
+	if remaining <= time.Second || remaining > 1100*time.Millisecond {
+		t.Errorf("Expected about 1.1s remaining after extending, got %v", remaining)
+	}
+	
+	timer.Pause()
+	timer.Extend(time.Second)
+	if timer.Remaining() <= 2*time.Second {
+		t.Errorf("Extending a paused timer should add to the remaining time, got %v", timer.Remaining())
+	}
+	
+	stopwatch := NewStopwatch()
+	stopwatch.Extend(time.Second)
+	if stopwatch.Remaining() != 0 {
+		t.Error("Extend should not affect count-up timers")
+	}
+}
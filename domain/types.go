@@ -15,8 +15,25 @@ const (
 	EventWorkSessionEnd    = "work_session_end"
 	EventBreakSessionEnd   = "break_session_end"
 	EventWarning           = "warning"
+	EventWarningGentle     = "warning_gentle"
+	EventWarningNotice     = "warning_notice"
+	EventWarningUrgent     = "warning_urgent"
+	EventWarningCritical   = "warning_critical"
 	EventSessionPause      = "session_pause"
 	EventSessionResume     = "session_resume"
+	EventSessionAbandon    = "session_abandon"
+	EventBreakSkipped      = "break_skip"
+	EventAutoStartSchedule = "auto_start_scheduled"
+	EventAutoStartCancel   = "auto_start_cancel"
+	EventAutoStart         = "auto_start"
+	EventOvertimeStart     = "overtime_start"
+	EventOvertimeEnd       = "overtime_end"
+	EventOvertimeCapped    = "overtime_cap"
+	EventFlowSessionStart  = "flow_session_start"
+	EventFlowSessionEnd    = "flow_session_end"
+	EventSessionExtended   = "session_extended"
+	EventPauseReminder     = "pause_reminder"
+	EventPauseExhausted    = "pause_budget_exhausted"
 )
 
 type SessionState int
@@ -25,6 +42,11 @@ const (
 	WorkSession SessionState = iota
 	BreakSession
 	Idle
+	// Overtime is work continued past the end of a work session, counted up
+	// until the user takes the break or the cap is reached.
+	Overtime
+	// FlowSession is open-ended work that counts up until the user stops it.
+	FlowSession
 )
 
 func (s SessionState) String() string {
@@ -35,6 +57,10 @@ func (s SessionState) String() string {
 		return "BreakSession"
 	case Idle:
 		return "Idle"
+	case Overtime:
+		return "Overtime"
+	case FlowSession:
+		return "FlowSession"
 	default:
 		return "Unknown"
 	}
@@ -45,6 +71,7 @@ type SessionType int
 const (
 	Work SessionType = iota
 	Break
+	Flow
 )
 
 func (t SessionType) String() string {
@@ -53,6 +80,8 @@ func (t SessionType) String() string {
 		return "Work"
 	case Break:
 		return "Break"
+	case Flow:
+		return "Flow"
 	default:
 		return "Unknown"
 	}
@@ -0,0 +1,112 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// WarningSeverity describes how strongly an idle warning should be delivered.
+type WarningSeverity int
+
+const (
+	WarningGentle WarningSeverity = iota
+	WarningNotice
+	WarningUrgent
+	WarningCritical
+)
+
+func (s WarningSeverity) String() string {
+	switch s {
+	case WarningGentle:
+		return "gentle"
+	case WarningNotice:
+		return "notice"
+	case WarningUrgent:
+		return "urgent"
+	case WarningCritical:
+		return "critical"
+	default:
+		return "unknown"
+	}
+}
+
+// EventName returns the session event fired for a warning of this severity.
+func (s WarningSeverity) EventName() string {
+	switch s {
+	case WarningGentle:
+		return EventWarningGentle
+	case WarningNotice:
+		return EventWarningNotice
+	case WarningUrgent:
+		return EventWarningUrgent
+	case WarningCritical:
+		return EventWarningCritical
+	default:
+		return EventWarning
+	}
+}
+
+func (s WarningSeverity) MarshalText() ([]byte, error) {
+	if s < WarningGentle || s > WarningCritical {
+		return nil, fmt.Errorf("%w: unknown warning severity %d", ErrInvalidConfig, int(s))
+	}
+	return []byte(s.String()), nil
+}
+
+func (s *WarningSeverity) UnmarshalText(text []byte) error {
+	name := strings.ToLower(strings.TrimSpace(string(text)))
+	for severity := WarningGentle; severity <= WarningCritical; severity++ {
+		if severity.String() == name {
+			*s = severity
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: unknown warning severity %q", ErrInvalidConfig, name)
+}
+
+// WarningStep is one rung of the idle-warning ladder. After is measured from
+// the moment the session became idle.
+type WarningStep struct {
+	After    time.Duration   `json:"after"`
+	Severity WarningSeverity `json:"severity"`
+}
+
+// DefaultWarningLadder returns the escalation used when nothing is configured.
+func DefaultWarningLadder() []WarningStep {
+	return []WarningStep{
+		{After: 2 * time.Minute, Severity: WarningGentle},
+		{After: 5 * time.Minute, Severity: WarningNotice},
+		{After: 10 * time.Minute, Severity: WarningUrgent},
+		{After: 15 * time.Minute, Severity: WarningCritical},
+	}
+}
+
+// ValidateWarningLadder checks that the ladder is non-empty and strictly increasing.
+func ValidateWarningLadder(steps []WarningStep) error {
+	if len(steps) == 0 {
+		return fmt.Errorf("%w: warning ladder is empty", ErrInvalidConfig)
+	}
+
+	var previous time.Duration
+	for i, step := range steps {
+		if step.After <= previous {
+			return fmt.Errorf("%w: warning step %d must come after %v", ErrInvalidConfig, i, previous)
+		}
+		if step.Severity < WarningGentle || step.Severity > WarningCritical {
+			return fmt.Errorf("%w: warning step %d has unknown severity", ErrInvalidConfig, i)
+		}
+		previous = step.After
+	}
+
+	return nil
+}
+
+// ValidateWarningRepeat checks the interval at which the last warning step
+// repeats.
+func ValidateWarningRepeat(repeat time.Duration) error {
+	if repeat <= 0 {
+		return ErrInvalidDuration
+	}
+	return nil
+}
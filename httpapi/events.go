@@ -0,0 +1,57 @@
+package httpapi
+
+import (
+	"sync"
+
+	"karedoro/application"
+)
+
+// subscriberBuffer is how many events a slow /events client may fall behind
+// before further events are dropped for it.
+const subscriberBuffer = 16
+
+// Event is one session event as sent on /events.
+type Event struct {
+	Name   string             `json:"event"`
+	Status application.Status `json:"status"`
+}
+
+// broker fans session events out to the connected /events clients. publish
+// is called on the session's goroutine and never blocks it.
+type broker struct {
+	mu          sync.Mutex
+	subscribers map[chan Event]struct{}
+}
+
+func newBroker() *broker {
+	return &broker{
+		subscribers: make(map[chan Event]struct{}),
+	}
+}
+
+func (b *broker) subscribe() chan Event {
+	ch := make(chan Event, subscriberBuffer)
+	
+	b.mu.Lock()
+	b.subscribers[ch] = struct{}{}
+	b.mu.Unlock()
+	return ch
+}
+
+func (b *broker) unsubscribe(ch chan Event) {
+	b.mu.Lock()
+	delete(b.subscribers, ch)
+	b.mu.Unlock()
+}
+
+func (b *broker) publish(event Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
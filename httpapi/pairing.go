@@ -0,0 +1,129 @@
+package httpapi
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+)
+
+// pairingAttempts is how many wrong codes are accepted before the code is
+// replaced. Together with the delay after each wrong code it keeps the code
+// from being guessed from the LAN.
+const (
+	pairingAttempts = 5
+	pairingDelay    = time.Second
+)
+
+// pairing hands out device tokens to web UI clients that present the code
+// shown by the running instance. Tokens live as long as the server.
+type pairing struct {
+	delay time.Duration
+	
+	mu       sync.Mutex
+	code     string
+	failures int
+	tokens   map[string]struct{}
+	
+	// clients serializes the attempts of each client address, so that the
+	// delay after a wrong code holds up only the client that sent it.
+	clients map[string]*pairingClient
+}
+
+type pairingClient struct {
+	sync.Mutex
+	
+	// waiting counts the attempts holding or waiting for the lock; the
+	// client is forgotten when it drops to zero.
+	waiting int
+}
+
+func newPairing() *pairing {
+	return &pairing{
+		delay:   pairingDelay,
+		code:    generateCode(),
+		tokens:  make(map[string]struct{}),
+		clients: make(map[string]*pairingClient),
+	}
+}
+
+// Code returns the current pairing code.
+func (p *pairing) Code() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.code
+}
+
+// Pair exchanges a correct code for a new device token. The attempts of a
+// client are taken one at a time, each wrong one followed by the delay.
+func (p *pairing) Pair(client, code string) (string, bool) {
+	c := p.lockClient(client)
+	defer p.unlockClient(client, c)
+	
+	token, ok := p.check(code)
+	if !ok {
+		time.Sleep(p.delay)
+	}
+	return token, ok
+}
+
+func (p *pairing) lockClient(client string) *pairingClient {
+	p.mu.Lock()
+	c := p.clients[client]
+	if c == nil {
+		c = &pairingClient{}
+		p.clients[client] = c
+	}
+	c.waiting++
+	p.mu.Unlock()
+	
+	c.Lock()
+	return c
+}
+
+func (p *pairing) unlockClient(client string, c *pairingClient) {
+	c.Unlock()
+	
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	c.waiting--
+	if c.waiting == 0 {
+		delete(p.clients, client)
+	}
+}
+
+func (p *pairing) check(code string) (string, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	
+	if subtle.ConstantTimeCompare([]byte(code), []byte(p.code)) != 1 {
+		p.failures++
+		if p.failures >= pairingAttempts {
+			p.code = generateCode()
+			p.failures = 0
+		}
+		return "", false
+	}
+	
+	token := generateToken()
+	p.tokens[token] = struct{}{}
+	p.failures = 0
+	return token, true
+}
+
+func (p *pairing) valid(token string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, ok := p.tokens[token]
+	return ok
+}
+
+func generateCode() string {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		panic(err)
+	}
+	return fmt.Sprintf("%06d", n.Int64())
+}
@@ -0,0 +1,76 @@
+package httpapi
+
+import (
+	"fmt"
+
+	"karedoro/application"
+)
+
+// redacted stands in for the secrets in config responses. A PUT that sends
+// it back keeps the current secret, so a config read from the API can be
+// edited and sent back as it is.
+const redacted = "(redacted)"
+
+// redactConfig returns a copy of config without its secrets: the API token
+// and the secrets and header values of the webhooks, which may carry
+// credentials.
+func redactConfig(config *application.Config) *application.Config {
+	config = config.Clone()
+	config.HTTP.Token = redact(config.HTTP.Token)
+	for i := range config.Webhooks {
+		webhook := &config.Webhooks[i]
+		webhook.Secret = redact(webhook.Secret)
+		for name, value := range webhook.Headers {
+			webhook.Headers[name] = redact(value)
+		}
+	}
+	return config
+}
+
+func redact(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return redacted
+}
+
+// restoreSecrets puts the secrets of current back where config still has
+// them redacted. Webhooks are matched by URL.
+func restoreSecrets(config, current *application.Config) error {
+	if config.HTTP.Token == redacted {
+		config.HTTP.Token = current.HTTP.Token
+	}
+	for i := range config.Webhooks {
+		webhook := &config.Webhooks[i]
+		previous := findWebhook(current.Webhooks, webhook.URL)
+		if webhook.Secret == redacted {
+			if previous == nil {
+				return fmt.Errorf("webhook %q: the secret is redacted; send the secret itself", webhook.URL)
+			}
+			webhook.Secret = previous.Secret
+		}
+		for name, value := range webhook.Headers {
+			if value != redacted {
+				continue
+			}
+			original, ok := "", false
+			if previous != nil {
+				original, ok = previous.Headers[name]
+			}
+			if !ok {
+				return fmt.Errorf("webhook %q: header %s is redacted; send its value itself", webhook.URL, name)
+			}
+			webhook.Headers[name] = original
+		}
+	}
+	return nil
+}
+
+func findWebhook(webhooks []application.WebhookConfig, url string) *application.WebhookConfig {
+	for i := range webhooks {
+		if webhooks[i].URL == url {
+			return &webhooks[i]
+		}
+	}
+	return nil
+}
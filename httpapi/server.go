@@ -0,0 +1,462 @@
+// Package httpapi serves karedoro's optional local REST API: session status
+// and control, configuration, and a Server-Sent Events stream of every
+// session event. It also serves a small web UI that pairs with the running
+// instance using a code shown in its window.
+package httpapi
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/subtle"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"karedoro/application"
+	"karedoro/ipc"
+)
+
+// maxConfigSize bounds the body accepted by PUT /api/config.
+const maxConfigSize = 1 << 20
+
+// errLocalOnly is returned for a PUT that changes settings which may only be
+// changed in the config file.
+var errLocalOnly = errors.New("these settings can only be changed in the config file")
+
+// restartHeader lists the settings a PUT changed that take effect only after
+// a restart.
+const restartHeader = "Karedoro-Restart-Required"
+
+// ErrNotLoopback is returned by Start when the configured address is not
+// local and LAN access has not been allowed.
+var ErrNotLoopback = errors.New("http api must listen on a loopback address unless allow_lan is set")
+
+//go:embed web
+var webFiles embed.FS
+
+// Runner runs a function on the goroutine that owns the session.
+type Runner interface {
+	Do(func(*application.SessionService)) error
+}
+
+// Server is the REST API and web UI as an http.Handler.
+type Server struct {
+	services *application.Services
+	runner   Runner
+	token    string
+	events   *broker
+	pairing  *pairing
+	mux      *http.ServeMux
+	
+	// httpServer is set when the server was started by Start.
+	httpServer *http.Server
+}
+
+// New builds the API. It subscribes to the session's events, so it must be
+// called before the session's loop starts or from the loop itself.
+func New(services *application.Services, runner Runner, token string) *Server {
+	s := &Server{
+		services: services,
+		runner:   runner,
+		token:    token,
+		events:   newBroker(),
+		pairing:  newPairing(),
+		mux:      http.NewServeMux(),
+	}
+	
+	services.Session.AddEventListener(func(eventName string) {
+		s.events.publish(Event{Name: eventName, Status: services.Session.Status()})
+	})
+	
+	s.mux.HandleFunc("GET /api/status", s.handleStatus)
+	s.mux.HandleFunc("POST /api/start/{kind}", s.handleStart)
+	s.mux.HandleFunc("POST /api/pause", s.handleCommand(ipc.CommandPause))
+	s.mux.HandleFunc("POST /api/resume", s.handleCommand(ipc.CommandResume))
+	s.mux.HandleFunc("POST /api/abandon", s.handleCommand(ipc.CommandAbandon))
+	s.mux.HandleFunc("POST /api/stop", s.handleCommand(ipc.CommandStop))
+	s.mux.HandleFunc("GET /api/config", s.handleGetConfig)
+	s.mux.HandleFunc("PUT /api/config", s.handlePutConfig)
+	s.mux.HandleFunc("GET /api/events", s.handleEvents)
+	s.mux.HandleFunc("GET /api/today", s.handleToday)
+	s.mux.HandleFunc("POST /api/pair", s.handlePair)
+	if services.Metrics != nil {
+		s.mux.HandleFunc("GET /metrics", s.handleMetrics)
+	}
+	
+	web, _ := fs.Sub(webFiles, "web")
+	s.mux.Handle("GET /", http.FileServer(http.FS(web)))
+	return s
+}
+
+// Start serves the API on the configured address if it is enabled, and
+// returns nil if it is not. The token is written to the runtime directory so
+// that local clients can find it.
+func Start(services *application.Services) (*Server, error) {
+	config := services.Config.GetConfig().HTTP
+	if !config.Enabled {
+		return nil, nil
+	}
+	if !config.AllowLAN && !isLoopback(config.Addr) {
+		return nil, fmt.Errorf("%w: %s", ErrNotLoopback, config.Addr)
+	}
+	
+	token := config.Token
+	if token == "" {
+		token = generateToken()
+	}
+	if err := writeToken(token); err != nil {
+		log.Printf("Warning: failed to write API token: %v", err)
+	}
+	
+	listener, err := net.Listen("tcp", config.Addr)
+	if err != nil {
+		return nil, err
+	}
+	
+	server := New(services, services.Loop, token)
+	server.httpServer = &http.Server{Handler: server}
+	go func() {
+		if err := server.httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("http api: %v", err)
+		}
+	}()
+	log.Printf("karedoro API listening on http://%s (web UI pairing code %s)", listener.Addr(), server.PairingCode())
+	return server, nil
+}
+
+// Close stops a server started by Start.
+func (s *Server) Close() error {
+	if s.httpServer == nil {
+		return nil
+	}
+	return s.httpServer.Close()
+}
+
+// PairingCode returns the code a web UI client must enter to pair.
+func (s *Server) PairingCode() string {
+	return s.pairing.Code()
+}
+
+// TokenPath returns where Start writes the API token.
+func TokenPath() string {
+	return filepath.Join(ipc.RuntimeDir(), "http-token")
+}
+
+// ServeHTTP requires a token for the API and metrics; the web UI's files and
+// the pairing endpoint are open.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	public := !strings.HasPrefix(r.URL.Path, "/api/") && r.URL.Path != "/metrics" || r.URL.Path == "/api/pair"
+	if !public && !s.authorized(r) {
+		writeError(w, http.StatusUnauthorized, "missing or invalid token")
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+// authorized accepts the API token or a paired device token, as a bearer
+// token or, for EventSource clients that cannot set headers, as the token
+// query parameter.
+func (s *Server) authorized(r *http.Request) bool {
+	given := r.URL.Query().Get("token")
+	if header := r.Header.Get("Authorization"); strings.HasPrefix(header, "Bearer ") {
+		given = strings.TrimPrefix(header, "Bearer ")
+	}
+	if s.token != "" && subtle.ConstantTimeCompare([]byte(given), []byte(s.token)) == 1 {
+		return true
+	}
+	return given != "" && s.pairing.valid(given)
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	s.execute(w, ipc.Request{Command: ipc.CommandStatus})
+}
+
+func (s *Server) handleStart(w http.ResponseWriter, r *http.Request) {
+	s.execute(w, ipc.Request{Command: ipc.CommandStart, Args: []string{r.PathValue("kind")}})
+}
+
+func (s *Server) handleCommand(command string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.execute(w, ipc.Request{Command: command})
+	}
+}
+
+func (s *Server) execute(w http.ResponseWriter, req ipc.Request) {
+	var status application.Status
+	var err error
+	runErr := s.runner.Do(func(sessionService *application.SessionService) {
+		err = ipc.Apply(sessionService, req)
+		status = sessionService.Status()
+	})
+	
+	switch {
+	case runErr != nil:
+		writeError(w, http.StatusServiceUnavailable, runErr.Error())
+	case errors.Is(err, ipc.ErrUsage):
+		writeError(w, http.StatusBadRequest, err.Error())
+	case err != nil:
+		writeError(w, http.StatusConflict, err.Error())
+	default:
+		writeJSON(w, http.StatusOK, ipc.Response{OK: true, Status: &status})
+	}
+}
+
+func (s *Server) handleToday(w http.ResponseWriter, r *http.Request) {
+	if s.services.Stats == nil {
+		writeJSON(w, http.StatusOK, application.DailyStats{})
+		return
+	}
+	
+	var today application.DailyStats
+	if err := s.runner.Do(func(*application.SessionService) {
+		today = s.services.Stats.Today()
+	}); err != nil {
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, today)
+}
+
+// handleMetrics serves the counters in the Prometheus text format, with
+// gauges for the current status.
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	var status application.Status
+	if err := s.runner.Do(func(sessionService *application.SessionService) {
+		status = sessionService.Status()
+	}); err != nil {
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	
+	w.Header().Set("Content-Type", application.MetricsContentType)
+	s.services.Metrics.Write(w, status)
+}
+
+// handlePair exchanges the pairing code for a device token.
+func (s *Server) handlePair(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Code string `json:"code"`
+	}
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxConfigSize)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	
+	token, ok := s.pairing.Pair(clientAddr(r), strings.TrimSpace(req.Code))
+	if !ok {
+		writeError(w, http.StatusForbidden, "wrong pairing code")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"token": token})
+}
+
+// handleGetConfig returns the configuration with its secrets redacted.
+func (s *Server) handleGetConfig(w http.ResponseWriter, r *http.Request) {
+	var config *application.Config
+	if err := s.runner.Do(func(*application.SessionService) {
+		config = redactConfig(s.services.Config.GetConfig())
+	}); err != nil {
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, config)
+}
+
+// handlePutConfig accepts a full or partial configuration. Fields that are
+// left out, and secrets sent back redacted, keep their current values.
+func (s *Server) handlePutConfig(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxConfigSize))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	
+	// 現在の設定の複製に書き込み、検証に失敗しても元の設定は変えない
+	var config *application.Config
+	var restart []string
+	runErr := s.runner.Do(func(sessionService *application.SessionService) {
+		current := s.services.Config.GetConfig()
+		if config, err = mergeConfig(current, body); err != nil {
+			return
+		}
+		if err = restoreSecrets(config, current); err != nil {
+			return
+		}
+		if changed := application.LocalOnlyChanges(current, config); len(changed) > 0 {
+			err = fmt.Errorf("%w: %s", errLocalOnly, strings.Join(changed, ", "))
+			return
+		}
+		restart = application.RestartChanges(current, config)
+		if err = s.services.ApplyConfig(config); err != nil {
+			return
+		}
+		config = redactConfig(config)
+	})
+	
+	switch {
+	case runErr != nil:
+		writeError(w, http.StatusServiceUnavailable, runErr.Error())
+	case errors.Is(err, errLocalOnly):
+		writeError(w, http.StatusForbidden, err.Error())
+	case err != nil:
+		writeError(w, http.StatusBadRequest, err.Error())
+	default:
+		if len(restart) > 0 {
+			w.Header().Set(restartHeader, strings.Join(restart, ", "))
+		}
+		writeJSON(w, http.StatusOK, config)
+	}
+}
+
+// mergeConfig applies the JSON object in patch to a copy of current. Objects
+// are merged key by key, so that fields left out keep their values, while
+// arrays and other values replace the current ones whole. Decoding patch
+// straight into the config would instead merge each array element into the
+// one it replaces, so that a replaced webhook kept the secret of the old one.
+func mergeConfig(current *application.Config, patch []byte) (*application.Config, error) {
+	base, err := json.Marshal(current)
+	if err != nil {
+		return nil, err
+	}
+	merged, err := decodeJSON(base)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := decodeJSON(patch)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := changes.(map[string]interface{}); !ok {
+		return nil, errors.New("config must be a JSON object")
+	}
+	
+	data, err := json.Marshal(mergeJSON(merged, changes))
+	if err != nil {
+		return nil, err
+	}
+	config := &application.Config{}
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// decodeJSON decodes data keeping numbers exact, since durations are
+// nanoseconds.
+func decodeJSON(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func mergeJSON(current, patch interface{}) interface{} {
+	currentObject, ok := current.(map[string]interface{})
+	patchObject, isObject := patch.(map[string]interface{})
+	if !ok || !isObject {
+		return patch
+	}
+	for key, value := range patchObject {
+		currentObject[key] = mergeJSON(currentObject[key], value)
+	}
+	return currentObject
+}
+
+// handleEvents streams session events as Server-Sent Events, starting with
+// the current status.
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming not supported")
+		return
+	}
+	
+	ch := s.events.subscribe()
+	defer s.events.unsubscribe(ch)
+	
+	var status application.Status
+	if err := s.runner.Do(func(sessionService *application.SessionService) {
+		status = sessionService.Status()
+	}); err != nil {
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	writeEvent(w, Event{Name: "status", Status: status})
+	flusher.Flush()
+	
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event := <-ch:
+			writeEvent(w, event)
+			flusher.Flush()
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, event Event) {
+	data, _ := json.Marshal(event)
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Name, data)
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, code int, message string) {
+	writeJSON(w, code, ipc.Response{Error: message})
+}
+
+func isLoopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// clientAddr is the host a request came from, without its port.
+func clientAddr(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func generateToken() string {
+	buf := make([]byte, 16)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+func writeToken(token string) error {
+	if err := ipc.MkdirPrivate(filepath.Dir(TokenPath())); err != nil {
+		return err
+	}
+	return os.WriteFile(TokenPath(), []byte(token+"\n"), 0600)
+}
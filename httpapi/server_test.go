@@ -0,0 +1,612 @@
+package httpapi
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"karedoro/application"
+	"karedoro/domain"
+	"karedoro/ipc"
+)
+
+const testToken = "secret"
+
+// newTestServer serves the API for a fresh session driven by a running loop.
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	return newTestServerWith(t, nil)
+}
+
+// newTestServerWith lets setup adjust the services before the API is built.
+func newTestServerWith(t *testing.T, setup func(*application.Services)) *httptest.Server {
+	t.Helper()
+	
+	tempDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", tempDir)
+	t.Cleanup(func() { os.Setenv("HOME", originalHome) })
+	
+	sessionService := application.NewSessionService()
+	services := &application.Services{
+		Session: sessionService,
+		Config:  application.NewConfigService(),
+		Loop:    application.NewLoop(sessionService, time.Millisecond),
+	}
+	if setup != nil {
+		setup(services)
+	}
+	api := New(services, services.Loop, testToken)
+	
+	ctx, cancel := context.WithCancel(context.Background())
+	go services.Loop.Run(ctx)
+	
+	server := httptest.NewServer(api)
+	t.Cleanup(func() {
+		server.Close()
+		cancel()
+	})
+	return server
+}
+
+func request(t *testing.T, server *httptest.Server, method, path, body string) (*http.Response, ipc.Response) {
+	t.Helper()
+	
+	req, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("Failed to build request: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	
+	var decoded ipc.Response
+	json.NewDecoder(resp.Body).Decode(&decoded)
+	return resp, decoded
+}
+
+func TestServer_RequiresToken(t *testing.T) {
+	server := newTestServer(t)
+	
+	resp, err := http.Get(server.URL + "/api/status")
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected 401 without a token, got %d", resp.StatusCode)
+	}
+	
+	resp, err = http.Get(server.URL + "/api/status?token=" + testToken)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected 200 with the token as a query parameter, got %d", resp.StatusCode)
+	}
+}
+
+func TestServer_SessionControl(t *testing.T) {
+	server := newTestServer(t)
+	
+	resp, body := request(t, server, http.MethodPost, "/api/start/work", "")
+	if resp.StatusCode != http.StatusOK || body.Status.State != domain.WorkSession.String() {
+		t.Fatalf("Expected a started work session, got %d %+v", resp.StatusCode, body)
+	}
+	
+	resp, _ = request(t, server, http.MethodPost, "/api/start/break", "")
+	if resp.StatusCode != http.StatusConflict {
+		t.Errorf("Expected 409 when a session is already running, got %d", resp.StatusCode)
+	}
+	
+	resp, _ = request(t, server, http.MethodPost, "/api/start/lunch", "")
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an unknown session kind, got %d", resp.StatusCode)
+	}
+	
+	resp, body = request(t, server, http.MethodPost, "/api/pause", "")
+	if resp.StatusCode != http.StatusOK || !body.Status.Paused {
+		t.Errorf("Expected a paused session, got %d %+v", resp.StatusCode, body)
+	}
+	
+	resp, body = request(t, server, http.MethodPost, "/api/abandon", "")
+	if resp.StatusCode != http.StatusOK || body.Status.State != domain.Idle.String() {
+		t.Errorf("Expected Idle after abandoning, got %d %+v", resp.StatusCode, body)
+	}
+}
+
+func TestServer_StopFlowSession(t *testing.T) {
+	server := newTestServer(t)
+	
+	resp, _ := request(t, server, http.MethodPost, "/api/stop", "")
+	if resp.StatusCode != http.StatusConflict {
+		t.Errorf("Expected 409 when nothing can be stopped, got %d", resp.StatusCode)
+	}
+	
+	request(t, server, http.MethodPost, "/api/start/flow", "")
+	resp, body := request(t, server, http.MethodPost, "/api/stop", "")
+	if resp.StatusCode != http.StatusOK || body.Status.State != domain.Idle.String() || !body.Status.BreakDue {
+		t.Errorf("Expected a due break after stopping the flow session, got %d %+v", resp.StatusCode, body)
+	}
+}
+
+func TestServer_Overtime(t *testing.T) {
+	var services *application.Services
+	server := newTestServerWith(t, func(s *application.Services) {
+		services = s
+		config := application.DefaultConfig()
+		config.WorkDuration = 10 * time.Millisecond
+		s.Session.Configure(config)
+	})
+	
+	// 作業を終えて残業に入る
+	startOvertime := func() {
+		t.Helper()
+		request(t, server, http.MethodPost, "/api/start/work", "")
+		time.Sleep(30 * time.Millisecond)
+		var err error
+		services.Loop.Do(func(sessionService *application.SessionService) {
+			err = sessionService.ContinueOvertime()
+		})
+		if err != nil {
+			t.Fatalf("ContinueOvertime should not return error, got %v", err)
+		}
+	}
+	
+	startOvertime()
+	resp, body := request(t, server, http.MethodPost, "/api/start/break", "")
+	if resp.StatusCode != http.StatusOK || body.Status.State != domain.BreakSession.String() {
+		t.Errorf("Expected a break from overtime, got %d %+v", resp.StatusCode, body)
+	}
+	request(t, server, http.MethodPost, "/api/abandon", "")
+	
+	startOvertime()
+	resp, _ = request(t, server, http.MethodPost, "/api/start/work", "")
+	if resp.StatusCode != http.StatusConflict {
+		t.Errorf("Expected 409 for work during overtime, got %d", resp.StatusCode)
+	}
+	resp, body = request(t, server, http.MethodPost, "/api/stop", "")
+	if resp.StatusCode != http.StatusOK || body.Status.State != domain.Idle.String() {
+		t.Errorf("Expected Idle after stopping overtime, got %d %+v", resp.StatusCode, body)
+	}
+}
+
+func TestServer_Config(t *testing.T) {
+	server := newTestServer(t)
+	
+	resp, _ := request(t, server, http.MethodPut, "/api/config", `{"work_duration": 0}`)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an invalid config, got %d", resp.StatusCode)
+	}
+	
+	resp, _ = request(t, server, http.MethodPut, "/api/config", `{"work_duration": 3000000000000}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200 for a valid config, got %d", resp.StatusCode)
+	}
+	
+	_, body := request(t, server, http.MethodPost, "/api/start/work", "")
+	if body.Status == nil || body.Status.Remaining != 3000 {
+		t.Errorf("Expected the new 50 minute work duration to apply, got %+v", body.Status)
+	}
+}
+
+func TestServer_RejectedConfigLeavesCurrentAlone(t *testing.T) {
+	var services *application.Services
+	server := newTestServerWith(t, func(s *application.Services) {
+		services = s
+	})
+	
+	// 単調増加でないラダーは検証で弾かれる
+	resp, _ := request(t, server, http.MethodPut, "/api/config",
+		`{"warning_ladder": [{"after": 600000000000}, {"after": 60000000000}]}`)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("Expected 400 for a decreasing ladder, got %d", resp.StatusCode)
+	}
+	
+	var ladder []domain.WarningStep
+	services.Loop.Do(func(*application.SessionService) {
+		ladder = services.Config.GetConfig().WarningLadder
+	})
+	if !reflect.DeepEqual(ladder, domain.DefaultWarningLadder()) {
+		t.Errorf("A rejected PUT changed the current ladder to %+v", ladder)
+	}
+}
+
+func TestServer_RejectedKeysDoNotStick(t *testing.T) {
+	var services *application.Services
+	server := newTestServerWith(t, func(s *application.Services) {
+		services = s
+	})
+	
+	resp, _ := request(t, server, http.MethodPut, "/api/config", `{"keys": {"dance": ["D"]}}`)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("Expected 400 for an unknown key action, got %d", resp.StatusCode)
+	}
+	
+	var keys application.Keymap
+	services.Loop.Do(func(*application.SessionService) {
+		keys = services.Config.GetConfig().Keys
+	})
+	if _, ok := keys["dance"]; ok {
+		t.Error("A rejected PUT added its action to the current keys")
+	}
+	
+	// 弾かれた変更が残っていると、以後の保存もすべて失敗する
+	resp, _ = request(t, server, http.MethodPut, "/api/config", `{"keys": {"pause": ["P"]}}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected a valid PUT after the rejected one to succeed, got %d", resp.StatusCode)
+	}
+}
+
+func TestServer_PutConfigReplacesArrays(t *testing.T) {
+	var services *application.Services
+	server := newTestServerWith(t, func(s *application.Services) {
+		services = s
+	})
+	
+	resp, _ := request(t, server, http.MethodPut, "/api/config", `{"warning_ladder": [{"after": 60000000000}]}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200 for a new ladder, got %d", resp.StatusCode)
+	}
+	
+	var ladder []domain.WarningStep
+	services.Loop.Do(func(*application.SessionService) {
+		ladder = services.Config.GetConfig().WarningLadder
+	})
+	want := []domain.WarningStep{{After: time.Minute}}
+	if !reflect.DeepEqual(ladder, want) {
+		t.Errorf("Expected the ladder to be replaced whole, got %+v", ladder)
+	}
+	
+	resp, _ = request(t, server, http.MethodPut, "/api/config", `["not", "an", "object"]`)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a config that is not an object, got %d", resp.StatusCode)
+	}
+}
+
+func TestServer_LocalOnlyConfigIsRejected(t *testing.T) {
+	var services *application.Services
+	server := newTestServerWith(t, func(s *application.Services) {
+		services = s
+	})
+	
+	// コマンドやファイルの場所を API から変えられると、任意のコマンドを実行できてしまう
+	for _, body := range []string{
+		`{"notifications": {"sinks": ["command"], "command": ["sh", "-c", "touch /tmp/pwned"]}}`,
+		`{"notifications": {"command": ["sh"]}}`,
+		`{"notifications": {"log_file": "/tmp/notifications.log"}}`,
+		`{"hooks": {"timeout": 60000000000}}`,
+		`{"webhooks": [{"url": "https://example.com/hook"}]}`,
+		`{"http": {"allow_lan": true}}`,
+		`{"font": "/etc/passwd"}`,
+	} {
+		resp, _ := request(t, server, http.MethodPut, "/api/config", body)
+		if resp.StatusCode != http.StatusForbidden {
+			t.Errorf("Expected 403 for %s, got %d", body, resp.StatusCode)
+		}
+	}
+	
+	var config *application.Config
+	services.Loop.Do(func(*application.SessionService) {
+		config = services.Config.GetConfig().Clone()
+	})
+	if len(config.Notifications.Command) != 0 || len(config.Webhooks) != 0 || config.HTTP.AllowLAN {
+		t.Errorf("A rejected PUT changed the config to %+v", config)
+	}
+	
+	// 変えずに送り返すだけなら通る
+	resp, _ := request(t, server, http.MethodPut, "/api/config", `{"notifications": {"sinks": [], "chain": ["desktop"]}}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected 200 for unchanged local-only settings, got %d", resp.StatusCode)
+	}
+}
+
+// recordingNotifier stands in for the desktop notifier.
+type recordingNotifier struct {
+	count int
+}
+
+func (r *recordingNotifier) Notify(application.Notification) error {
+	r.count++
+	return nil
+}
+
+func TestServer_NotificationChangesApply(t *testing.T) {
+	var services *application.Services
+	desktop := &recordingNotifier{}
+	server := newTestServerWith(t, func(s *application.Services) {
+		services = s
+		notificationService := application.NewNotificationService()
+		notificationService.SetNotifier(desktop)
+		s.Notification = notificationService
+	})
+	
+	resp, _ := request(t, server, http.MethodPut, "/api/config", `{"notifications": {"chain": ["log"]}}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200 for a new chain, got %d", resp.StatusCode)
+	}
+	
+	// 再起動しなくても新しい通知先に届く
+	var dir string
+	services.Loop.Do(func(*application.SessionService) {
+		dir = services.Config.Dir()
+		services.Notification.ShowSessionPaused()
+	})
+	if desktop.count != 0 {
+		t.Errorf("Expected the desktop sink to be dropped, got %d notifications", desktop.count)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "notifications.log")); err != nil {
+		t.Errorf("Expected the log sink to write the notification, got %v", err)
+	}
+	if header := resp.Header.Get(restartHeader); header != "" {
+		t.Errorf("Expected no restart for a notification change, got %q", header)
+	}
+	
+	resp, _ = request(t, server, http.MethodPut, "/api/config", `{"locale": "ja", "keys": {"pause": ["P"]}}`)
+	if header := resp.Header.Get(restartHeader); header != "locale, keys" {
+		t.Errorf("Expected locale and keys to need a restart, got %q", header)
+	}
+}
+
+func TestServer_ConfigSecretsAreRedacted(t *testing.T) {
+	var services *application.Services
+	server := newTestServerWith(t, func(s *application.Services) {
+		services = s
+		config := s.Config.GetConfig()
+		config.HTTP.Token = "api-token"
+		config.Webhooks = []application.WebhookConfig{
+			{URL: "https://example.com/hook", Secret: "hook-secret", Headers: map[string]string{"Authorization": "Bearer hook-key"}},
+		}
+	})
+	
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/api/config", nil)
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	for _, secret := range []string{"api-token", "hook-secret", "hook-key"} {
+		if strings.Contains(string(body), secret) {
+			t.Errorf("GET /api/config returned %q", secret)
+		}
+	}
+	
+	// 取得した設定をそのまま送り返しても秘密は変わらない
+	resp, _ = request(t, server, http.MethodPut, "/api/config", string(body))
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200 for the config sent back, got %d", resp.StatusCode)
+	}
+	var config *application.Config
+	services.Loop.Do(func(*application.SessionService) {
+		config = services.Config.GetConfig().Clone()
+	})
+	if config.HTTP.Token != "api-token" {
+		t.Errorf("Expected the token to be kept, got %q", config.HTTP.Token)
+	}
+	if webhook := config.Webhooks[0]; webhook.Secret != "hook-secret" || webhook.Headers["Authorization"] != "Bearer hook-key" {
+		t.Errorf("Expected the webhook secrets to be kept, got %+v", webhook)
+	}
+	
+	// 知らない URL の秘密は戻せない
+	resp, _ = request(t, server, http.MethodPut, "/api/config",
+		`{"webhooks": [{"url": "https://example.com/other", "secret": "`+redacted+`"}]}`)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a redacted secret of a new webhook, got %d", resp.StatusCode)
+	}
+}
+
+func TestServer_EventStream(t *testing.T) {
+	server := newTestServer(t)
+	
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/api/events?token="+testToken, nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("Expected an event stream, got %q", resp.Header.Get("Content-Type"))
+	}
+	
+	events := make(chan string, 8)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			if name, ok := strings.CutPrefix(scanner.Text(), "event: "); ok {
+				events <- name
+			}
+		}
+	}()
+	
+	if name := <-events; name != "status" {
+		t.Fatalf("Expected the initial status event, got %q", name)
+	}
+	
+	request(t, server, http.MethodPost, "/api/start/work", "")
+	
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case name := <-events:
+			if name == domain.EventWorkSessionStart {
+				return
+			}
+		case <-timeout:
+			t.Fatal("Expected a work_session_start event")
+		}
+	}
+}
+
+func TestServer_WebUIAndPairing(t *testing.T) {
+	tempDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", tempDir)
+	defer os.Setenv("HOME", originalHome)
+	
+	sessionService := application.NewSessionService()
+	services := &application.Services{
+		Session: sessionService,
+		Config:  application.NewConfigService(),
+		Loop:    application.NewLoop(sessionService, time.Millisecond),
+	}
+	api := New(services, services.Loop, testToken)
+	api.pairing.delay = 0
+	
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go services.Loop.Run(ctx)
+	server := httptest.NewServer(api)
+	defer server.Close()
+	
+	resp, err := http.Get(server.URL + "/")
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/html") {
+		t.Errorf("Expected the web UI without a token, got %d %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	
+	resp, err = http.Post(server.URL+"/api/pair", "application/json", strings.NewReader(`{"code":"wrong"}`))
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("Expected 403 for a wrong code, got %d", resp.StatusCode)
+	}
+	
+	resp, err = http.Post(server.URL+"/api/pair", "application/json", strings.NewReader(`{"code":"`+api.PairingCode()+`"}`))
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	var paired struct {
+		Token string `json:"token"`
+	}
+	json.NewDecoder(resp.Body).Decode(&paired)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || paired.Token == "" {
+		t.Fatalf("Expected a device token, got %d", resp.StatusCode)
+	}
+	
+	resp, err = http.Get(server.URL + "/api/today?token=" + paired.Token)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected the device token to be accepted, got %d", resp.StatusCode)
+	}
+}
+
+func TestServer_Metrics(t *testing.T) {
+	server := newTestServerWith(t, func(services *application.Services) {
+		services.Metrics = application.NewMetrics()
+		services.Metrics.Attach(services.Session)
+	})
+	
+	resp, err := http.Get(server.URL + "/metrics")
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected 401 without a token, got %d", resp.StatusCode)
+	}
+	
+	request(t, server, http.MethodPost, "/api/start/work", "")
+	request(t, server, http.MethodPost, "/api/pause", "")
+	
+	resp, err = http.Get(server.URL + "/metrics?token=" + testToken)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	
+	if resp.Header.Get("Content-Type") != application.MetricsContentType {
+		t.Errorf("Unexpected content type %q", resp.Header.Get("Content-Type"))
+	}
+	for _, expected := range []string{
+		"karedoro_pauses_total 1",
+		`karedoro_state{state="WorkSession"} 1`,
+		"karedoro_paused 1",
+	} {
+		if !strings.Contains(string(body), expected) {
+			t.Errorf("Metrics should contain %q, got:\n%s", expected, body)
+		}
+	}
+}
+
+func TestServer_MetricsDisabled(t *testing.T) {
+	server := newTestServer(t)
+	
+	resp, err := http.Get(server.URL + "/metrics?token=" + testToken)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 when metrics are disabled, got %d", resp.StatusCode)
+	}
+}
+
+func TestPairing_RotatesCodeAfterFailures(t *testing.T) {
+	p := newPairing()
+	p.delay = 0
+	code := p.Code()
+	
+	for i := 0; i < pairingAttempts-1; i++ {
+		p.Pair("192.0.2.1", "not a code")
+	}
+	if p.Code() != code {
+		t.Fatal("Pairing code should survive a few wrong attempts")
+	}
+	
+	p.Pair("192.0.2.1", "not a code")
+	if p.Code() == code {
+		t.Error("Pairing code should be replaced after repeated failures")
+	}
+}
+
+func TestPairing_DelaysOnlyTheWrongClient(t *testing.T) {
+	p := newPairing()
+	p.delay = 500 * time.Millisecond
+	
+	done := make(chan struct{})
+	go func() {
+		p.Pair("192.0.2.1", "not a code")
+		close(done)
+	}()
+	time.Sleep(50 * time.Millisecond)
+	
+	// 別のクライアントは、間違えたクライアントの待ち時間に巻き込まれない
+	start := time.Now()
+	if _, ok := p.Pair("192.0.2.2", p.Code()); !ok {
+		t.Fatal("Expected the right code to pair")
+	}
+	if elapsed := time.Since(start); elapsed > 250*time.Millisecond {
+		t.Errorf("Another client waited %v for the wrong attempt", elapsed)
+	}
+	
+	<-done
+	if len(p.clients) != 0 {
+		t.Errorf("Expected finished clients to be forgotten, got %d", len(p.clients))
+	}
+}
@@ -0,0 +1,125 @@
+// karedoro web UI: mirrors the main screen of the window and drives the
+// session through the local API.
+(function () {
+  "use strict";
+
+  var tokenKey = "karedoro-token";
+  var token = localStorage.getItem(tokenKey);
+
+  function $(id) { return document.getElementById(id); }
+
+  function api(method, path, body) {
+    return fetch(path, {
+      method: method,
+      headers: { "Authorization": "Bearer " + token, "Content-Type": "application/json" },
+      body: body ? JSON.stringify(body) : undefined
+    }).then(function (resp) {
+      if (resp.status === 401) {
+        localStorage.removeItem(tokenKey);
+        showPairing();
+        throw new Error("unauthorized");
+      }
+      return resp.json();
+    });
+  }
+
+  function clock(seconds) {
+    var m = Math.floor(seconds / 60), s = seconds % 60;
+    return (m < 10 ? "0" : "") + m + ":" + (s < 10 ? "0" : "") + s;
+  }
+
+  var stateClasses = { WorkSession: "work", BreakSession: "break", FlowSession: "flow", Overtime: "overtime" };
+  var stateLabels = { WorkSession: "WORKING", BreakSession: "BREAK TIME", FlowSession: "FLOW", Overtime: "OVERTIME", Idle: "Ready to start" };
+
+  function button(label, action) {
+    var b = document.createElement("button");
+    b.textContent = label;
+    b.onclick = function () { api("POST", "/api/" + action).then(refresh); };
+    return b;
+  }
+
+  function render(status) {
+    document.body.className = stateClasses[status.state] || "";
+
+    var label = stateLabels[status.state] || status.state;
+    if (status.paused) { label += " (PAUSED)"; }
+    if (status.state === "Idle" && status.break_due) { label = "Time for a break!"; }
+    if (status.auto_start_in) { label += " - next session in " + status.auto_start_in + "s"; }
+    $("state").textContent = label;
+
+    var counting = status.state === "FlowSession" || status.state === "Overtime";
+    $("clock").textContent = (status.state === "Overtime" ? "+" : "") + clock(counting ? status.elapsed : status.remaining);
+    $("progress-bar").style.width = Math.round(status.progress * 100) + "%";
+
+    var buttons = $("buttons");
+    buttons.innerHTML = "";
+    switch (status.state) {
+    case "Idle":
+      if (status.break_due) {
+        buttons.appendChild(button("START BREAK", "start/break"));
+      } else {
+        buttons.appendChild(button("START WORK SESSION", "start/work"));
+        buttons.appendChild(button("START FLOW SESSION", "start/flow"));
+      }
+      break;
+    case "Overtime":
+      buttons.appendChild(button("START BREAK", "start/break"));
+      buttons.appendChild(button("STOP", "stop"));
+      break;
+    default:
+      if (status.state === "FlowSession") {
+        buttons.appendChild(button("STOP", "stop"));
+      }
+      if (status.paused) {
+        buttons.appendChild(button("RESUME", "resume"));
+      } else if (status.can_pause) {
+        buttons.appendChild(button("PAUSE", "pause"));
+      }
+      buttons.appendChild(button("ABANDON", "abandon"));
+    }
+  }
+
+  function refresh() {
+    return Promise.all([api("GET", "/api/status"), api("GET", "/api/today")]).then(function (results) {
+      render(results[0].status);
+      $("today").textContent = "Today: " + results[1].work_sessions_completed + " pomodoros";
+    }).catch(function () {});
+  }
+
+  var timer = null;
+
+  function showTimer() {
+    $("pair").hidden = true;
+    $("timer").hidden = false;
+    refresh();
+    if (!timer) { timer = setInterval(refresh, 1000); }
+  }
+
+  function showPairing() {
+    if (timer) { clearInterval(timer); timer = null; }
+    $("timer").hidden = true;
+    $("pair").hidden = false;
+    $("pair-code").focus();
+  }
+
+  $("pair-form").onsubmit = function (e) {
+    e.preventDefault();
+    fetch("/api/pair", {
+      method: "POST",
+      headers: { "Content-Type": "application/json" },
+      body: JSON.stringify({ code: $("pair-code").value })
+    }).then(function (resp) {
+      return resp.json().then(function (body) {
+        if (!resp.ok) { throw new Error(body.error); }
+        token = body.token;
+        localStorage.setItem(tokenKey, token);
+        $("pair-error").textContent = "";
+        showTimer();
+      });
+    }).catch(function (err) {
+      $("pair-error").textContent = err.message;
+    });
+  };
+
+  if (token) { showTimer(); } else { showPairing(); }
+})();
@@ -0,0 +1,203 @@
+// Package i18n holds karedoro's message catalog: the notification and
+// screen texts as templates, in English and Japanese, with user overrides
+// from the config directory.
+package i18n
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"karedoro/domain"
+)
+
+// DefaultLocale is used for keys a locale does not translate.
+const DefaultLocale = "en"
+
+//go:embed locales/*.json
+var bundles embed.FS
+
+// Data is what message templates can refer to. Each message uses only the
+// fields that apply to it.
+type Data struct {
+	// Duration is the length the message is about, such as the session
+	// that starts or the break that is due.
+	Duration Duration
+	
+	CompletedToday int
+	SkippedToday   int
+	
+	// Session is the localized name of a session type.
+	Session string
+	
+	Count int
+	Limit int
+	Code  string
+	
+	// Keys are the labels of the window's keys by action, such as "SPACE"
+	// for "pause".
+	Keys map[string]string
+}
+
+// Duration prints without trailing zero units, e.g. "25m" or "1h30m".
+type Duration time.Duration
+
+func (d Duration) String() string {
+	s := time.Duration(d).Round(time.Second).String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// funcs are available to every template.
+var funcs = template.FuncMap{
+	// 開始直後の残り時間 (24:59.9) も 25 分と読めるよう四捨五入する
+	"minutes": func(d Duration) int {
+		return int(time.Duration(d).Round(time.Minute).Minutes())
+	},
+	"clock": func(d Duration) string {
+		return fmt.Sprintf("%02d:%02d", int(time.Duration(d).Minutes()), int(time.Duration(d).Seconds())%60)
+	},
+}
+
+// Catalog renders messages for one locale.
+type Catalog struct {
+	locale   string
+	messages map[string]*template.Template
+}
+
+// Locales lists the built-in locales.
+func Locales() []string {
+	entries, _ := bundles.ReadDir("locales")
+	var locales []string
+	for _, entry := range entries {
+		locales = append(locales, strings.TrimSuffix(entry.Name(), ".json"))
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// DetectLocale picks the built-in locale matching LC_ALL, LC_MESSAGES or
+// LANG, falling back to DefaultLocale.
+func DetectLocale(getenv func(string) string) string {
+	for _, key := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		value := getenv(key)
+		if value == "" {
+			continue
+		}
+		for _, locale := range Locales() {
+			if strings.HasPrefix(value, locale) {
+				return locale
+			}
+		}
+		return DefaultLocale
+	}
+	return DefaultLocale
+}
+
+// Load builds the catalog for locale, detected from the environment when
+// empty. Keys the locale lacks fall back to English, and templates in
+// dir/messages/<locale>.json, if it exists, override the built-in ones.
+func Load(locale, dir string) (*Catalog, error) {
+	if locale == "" {
+		locale = DetectLocale(os.Getenv)
+	}
+	
+	c := &Catalog{locale: locale, messages: make(map[string]*template.Template)}
+	if err := c.addBundle(DefaultLocale); err != nil {
+		return nil, err
+	}
+	if locale != DefaultLocale {
+		if err := c.addBundle(locale); err != nil {
+			return nil, err
+		}
+	}
+	
+	if dir == "" {
+		return c, nil
+	}
+	path := filepath.Join(dir, "messages", locale+".json")
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := c.add(data); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return c, nil
+}
+
+// Default returns the built-in English catalog.
+func Default() *Catalog {
+	c, err := Load(DefaultLocale, "")
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+func (c *Catalog) addBundle(locale string) error {
+	data, err := bundles.ReadFile("locales/" + locale + ".json")
+	if err != nil {
+		return fmt.Errorf("unknown locale %q (known: %s)", locale, strings.Join(Locales(), ", "))
+	}
+	return c.add(data)
+}
+
+// add parses a JSON object of key to template, checking that each template
+// renders.
+func (c *Catalog) add(data []byte) error {
+	var sources map[string]string
+	if err := json.Unmarshal(data, &sources); err != nil {
+		return err
+	}
+	
+	for key, source := range sources {
+		tmpl, err := template.New(key).Funcs(funcs).Parse(source)
+		if err != nil {
+			return err
+		}
+		if err := tmpl.Execute(&bytes.Buffer{}, Data{}); err != nil {
+			return err
+		}
+		c.messages[key] = tmpl
+	}
+	return nil
+}
+
+// Locale returns the locale of the catalog.
+func (c *Catalog) Locale() string {
+	return c.locale
+}
+
+// Text renders the message for key. An unknown key renders as itself.
+func (c *Catalog) Text(key string, data Data) string {
+	tmpl, ok := c.messages[key]
+	if !ok {
+		return key
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return key
+	}
+	return buf.String()
+}
+
+// SessionType returns the localized name of a session type.
+func (c *Catalog) SessionType(sessionType domain.SessionType) string {
+	return c.Text("session."+strings.ToLower(sessionType.String()), Data{})
+}
@@ -0,0 +1,146 @@
+package i18n
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"karedoro/domain"
+)
+
+func bundleKeys(t *testing.T, locale string) map[string]bool {
+	t.Helper()
+	data, err := bundles.ReadFile("locales/" + locale + ".json")
+	if err != nil {
+		t.Fatalf("Failed to read the %s bundle: %v", locale, err)
+	}
+	var sources map[string]string
+	if err := json.Unmarshal(data, &sources); err != nil {
+		t.Fatalf("Failed to parse the %s bundle: %v", locale, err)
+	}
+	keys := make(map[string]bool)
+	for key := range sources {
+		keys[key] = true
+	}
+	return keys
+}
+
+func TestBundles_SameKeys(t *testing.T) {
+	english := bundleKeys(t, DefaultLocale)
+	for _, locale := range Locales() {
+		keys := bundleKeys(t, locale)
+		for key := range english {
+			if !keys[key] {
+				t.Errorf("%s bundle is missing %s", locale, key)
+			}
+		}
+		for key := range keys {
+			if !english[key] {
+				t.Errorf("%s bundle has %s, which English lacks", locale, key)
+			}
+		}
+	}
+}
+
+func TestCatalog_Text(t *testing.T) {
+	c := Default()
+	
+	text := c.Text("notify.work_start", Data{Duration: Duration(50 * time.Minute)})
+	if !strings.Contains(text, "Focus for 50 minutes") {
+		t.Errorf("Unexpected work start message %q", text)
+	}
+	if text := c.Text("skip.cooldown", Data{Duration: Duration(90 * time.Second)}); !strings.HasSuffix(text, "01:30") {
+		t.Errorf("Expected a clock, got %q", text)
+	}
+	if text := c.Text("gui.pause", Data{Keys: map[string]string{"pause": "P"}}); text != "Press P to pause" {
+		t.Errorf("Expected the configured key, got %q", text)
+	}
+	if text := c.Text("no.such.key", Data{}); text != "no.such.key" {
+		t.Errorf("Unknown keys should render as themselves, got %q", text)
+	}
+	if name := c.SessionType(domain.Flow); name != "Flow" {
+		t.Errorf("Expected Flow, got %q", name)
+	}
+}
+
+func TestLoad_Japanese(t *testing.T) {
+	c, err := Load("ja", "")
+	if err != nil {
+		t.Fatalf("Load should not return error, got %v", err)
+	}
+	if c.Locale() != "ja" {
+		t.Errorf("Expected ja, got %s", c.Locale())
+	}
+	if text := c.Text("notify.break_start", Data{Duration: Duration(5 * time.Minute)}); !strings.Contains(text, "5分間") {
+		t.Errorf("Unexpected break start message %q", text)
+	}
+	
+	if _, err := Load("xx", ""); err == nil {
+		t.Error("Load should reject an unknown locale")
+	}
+}
+
+func TestLoad_Overrides(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "messages"), 0755)
+	override := `{"screen.working": "DEEP WORK ({{.CompletedToday}} done)"}`
+	if err := os.WriteFile(filepath.Join(dir, "messages", "en.json"), []byte(override), 0644); err != nil {
+		t.Fatal(err)
+	}
+	
+	c, err := Load("en", dir)
+	if err != nil {
+		t.Fatalf("Load should not return error, got %v", err)
+	}
+	if text := c.Text("screen.working", Data{CompletedToday: 3}); text != "DEEP WORK (3 done)" {
+		t.Errorf("Expected the override, got %q", text)
+	}
+	if text := c.Text("screen.break", Data{}); text != "BREAK TIME - RELAX!" {
+		t.Errorf("Other keys should keep the built-in text, got %q", text)
+	}
+	
+	os.WriteFile(filepath.Join(dir, "messages", "en.json"), []byte(`{"screen.working": "{{.NoSuchField}}"}`), 0644)
+	if _, err := Load("en", dir); err == nil {
+		t.Error("Load should reject a template that cannot render")
+	}
+}
+
+func TestDetectLocale(t *testing.T) {
+	tests := []struct {
+		env  map[string]string
+		want string
+	}{
+		{map[string]string{"LANG": "ja_JP.UTF-8"}, "ja"},
+		{map[string]string{"LANG": "ja_JP.UTF-8", "LC_ALL": "C"}, "en"},
+		{map[string]string{"LC_MESSAGES": "ja_JP.UTF-8", "LANG": "en_US.UTF-8"}, "ja"},
+		{map[string]string{}, "en"},
+	}
+	for _, tt := range tests {
+		getenv := func(key string) string { return tt.env[key] }
+		if got := DetectLocale(getenv); got != tt.want {
+			t.Errorf("DetectLocale(%v) = %s, want %s", tt.env, got, tt.want)
+		}
+	}
+}
+
+func TestDuration_String(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{25 * time.Minute, "25m"},
+		{30 * time.Minute, "30m"},
+		{90 * time.Minute, "1h30m"},
+		{time.Hour, "1h"},
+		{45 * time.Second, "45s"},
+		{0, "0s"},
+	}
+	for _, tt := range tests {
+		if got := Duration(tt.d).String(); got != tt.want {
+			t.Errorf("Duration(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
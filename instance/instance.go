@@ -0,0 +1,129 @@
+// Package instance keeps karedoro to a single timer per user. The first
+// process to start holds the lock and answers the control socket; later
+// launches hand their action to it and exit.
+package instance
+
+import (
+	"log"
+	"sync"
+
+	"karedoro/application"
+	"karedoro/ipc"
+)
+
+// Instance is the running process's claim on the lock file and socket.
+type Instance struct {
+	socketPath string
+	lock       *ipc.Lock
+	server     *ipc.Server
+	snapshot   *application.SnapshotWriter
+}
+
+// Acquire takes the lock file next to socketPath. It returns
+// ipc.ErrAlreadyRunning if another instance holds it.
+func Acquire(socketPath string) (*Instance, error) {
+	lock, err := ipc.AcquireLock(ipc.LockPath(socketPath))
+	if err != nil {
+		return nil, err
+	}
+	return &Instance{
+		socketPath: socketPath,
+		lock:       lock,
+	}, nil
+}
+
+// Listen starts answering requests on socketPath with handler. Holding the
+// lock, any socket already there was left behind by a crashed process.
+func (i *Instance) Listen(socketPath string, handler ipc.Handler) error {
+	server, err := ipc.Listen(socketPath, handler)
+	if err != nil {
+		return err
+	}
+	i.server = server
+	
+	go func() {
+		if err := server.Serve(); err != nil {
+			log.Printf("instance: %v", err)
+		}
+	}()
+	return nil
+}
+
+// Publish keeps the state snapshot next to the socket up to date for
+// status bars. It must be called before the session's loop starts.
+func (i *Instance) Publish(sessionService *application.SessionService) {
+	i.snapshot = application.NewSnapshotWriter(ipc.SnapshotPath(i.socketPath))
+	i.snapshot.Attach(sessionService)
+}
+
+// Close stops the socket, removes the snapshot and releases the lock.
+func (i *Instance) Close() error {
+	if i.server != nil {
+		i.server.Close()
+	}
+	if i.snapshot != nil {
+		i.snapshot.Remove()
+	}
+	return i.lock.Release()
+}
+
+// Handler answers control requests by running them on a session loop.
+type Handler struct {
+	loop *application.Loop
+	
+	mu          sync.Mutex
+	raise       func()
+	pairingCode func() string
+}
+
+func NewHandler(loop *application.Loop) *Handler {
+	return &Handler{loop: loop}
+}
+
+// SetRaise sets how the front-end brings itself to the front. It is called
+// on the loop's goroutine.
+func (h *Handler) SetRaise(raise func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.raise = raise
+}
+
+// SetPairingCode sets where pair requests get the web UI pairing code.
+func (h *Handler) SetPairingCode(code func() string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.pairingCode = code
+}
+
+// Handle runs a request on the session's loop.
+func (h *Handler) Handle(req ipc.Request) ipc.Response {
+	h.mu.Lock()
+	raise, pairingCode := h.raise, h.pairingCode
+	h.mu.Unlock()
+	
+	if req.Command == ipc.CommandPair {
+		if pairingCode == nil {
+			return ipc.Errorf("the web UI is not enabled (set http.enabled in the config)")
+		}
+		return ipc.Response{OK: true, PairingCode: pairingCode()}
+	}
+	
+	var resp ipc.Response
+	err := h.loop.Do(func(sessionService *application.SessionService) {
+		if req.Command != ipc.CommandRaise {
+			resp = ipc.Execute(sessionService, req)
+			return
+		}
+		
+		// ウィンドウのないフロントエンドでは状態を返すだけ
+		if raise != nil {
+			raise()
+		}
+		status := sessionService.Status()
+		resp = ipc.Response{OK: true, Status: &status}
+	})
+	if err != nil {
+		return ipc.Errorf("%v", err)
+	}
+	return resp
+}
@@ -0,0 +1,79 @@
+package instance
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"karedoro/application"
+	"karedoro/domain"
+	"karedoro/ipc"
+)
+
+func TestAcquire_SingleInstance(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run", "karedoro.sock")
+	
+	inst, err := Acquire(path)
+	if err != nil {
+		t.Fatalf("Acquire should not return error, got %v", err)
+	}
+	if _, err := Acquire(path); !errors.Is(err, ipc.ErrAlreadyRunning) {
+		t.Errorf("Second Acquire should return ErrAlreadyRunning, got %v", err)
+	}
+	
+	inst.Close()
+	inst, err = Acquire(path)
+	if err != nil {
+		t.Fatalf("Acquire should succeed once the first instance has closed, got %v", err)
+	}
+	inst.Close()
+}
+
+func TestHandler_HandOff(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run", "karedoro.sock")
+	sessionService := application.NewSessionService()
+	loop := application.NewLoop(sessionService, application.LoopInterval)
+	
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go loop.Run(ctx)
+	
+	raised := false
+	handler := NewHandler(loop)
+	handler.SetRaise(func() {
+		raised = true
+	})
+	
+	inst, err := Acquire(path)
+	if err != nil {
+		t.Fatalf("Acquire should not return error, got %v", err)
+	}
+	defer inst.Close()
+	if err := inst.Listen(path, handler.Handle); err != nil {
+		t.Fatalf("Listen should not return error, got %v", err)
+	}
+	
+	resp, err := ipc.Call(path, ipc.Request{Command: ipc.CommandRaise})
+	if err != nil {
+		t.Fatalf("Raise should not return error, got %v", err)
+	}
+	if !raised {
+		t.Error("Raise should bring the front-end to the front")
+	}
+	if resp.Status == nil || resp.Status.State != domain.Idle.String() {
+		t.Errorf("Raise should answer with the status, got %+v", resp.Status)
+	}
+	
+	resp, err = ipc.Call(path, ipc.Request{Command: ipc.CommandStart, Args: []string{"work"}})
+	if err != nil {
+		t.Fatalf("Forwarded start should not return error, got %v", err)
+	}
+	if resp.Status.State != domain.WorkSession.String() {
+		t.Errorf("Expected WorkSession, got %v", resp.Status.State)
+	}
+	
+	if _, err := ipc.Call(path, ipc.Request{Command: ipc.CommandPair}); err == nil {
+		t.Error("Pair should fail without the web UI")
+	}
+}
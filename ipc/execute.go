@@ -0,0 +1,96 @@
+package ipc
+
+import (
+	"errors"
+	"fmt"
+
+	"karedoro/application"
+	"karedoro/domain"
+)
+
+// ErrUsage is returned for requests with an unknown command or bad arguments.
+var ErrUsage = errors.New("usage")
+
+// Execute applies req to the session. It must be called from the goroutine
+// that owns sessionService.
+func Execute(sessionService *application.SessionService, req Request) Response {
+	if err := Apply(sessionService, req); err != nil {
+		return Errorf("%v", err)
+	}
+	
+	status := sessionService.Status()
+	return Response{OK: true, Status: &status}
+}
+
+// Apply performs req on the session without building a response. Requests
+// that make no sense in the current state fail with domain.ErrInvalidState;
+// malformed ones with ErrUsage.
+func Apply(sessionService *application.SessionService, req Request) error {
+	session := sessionService.GetSession()
+	
+	switch req.Command {
+	case CommandStatus:
+		return nil
+	case CommandStart:
+		if len(req.Args) != 1 {
+			return errUsage("start work|break|flow")
+		}
+		var start func() error
+		switch req.Args[0] {
+		case "work":
+			start = sessionService.StartWorkSession
+		case "break":
+			start = sessionService.StartBreakSession
+		case "flow":
+			start = sessionService.StartFlowSession
+		default:
+			return errUsage("start work|break|flow")
+		}
+		// 残業中は休憩を始めれば残業が締まる
+		overtimeBreak := req.Args[0] == "break" && session.GetState() == domain.Overtime
+		if session.IsSessionActive() && !overtimeBreak {
+			return domain.NewSessionError("start", domain.ErrInvalidState)
+		}
+		return start()
+	case CommandPause:
+		if !isTimed(session) || session.IsSessionPaused() {
+			return domain.NewSessionError("pause", domain.ErrInvalidState)
+		}
+		return sessionService.PauseSession()
+	case CommandResume:
+		if !session.IsSessionPaused() {
+			return domain.NewSessionError("resume", domain.ErrInvalidState)
+		}
+		return sessionService.ResumeSession()
+	case CommandStop:
+		switch session.GetState() {
+		case domain.FlowSession:
+			return sessionService.StopFlowSession()
+		case domain.Overtime:
+			return sessionService.StopOvertime()
+		default:
+			return domain.NewSessionError("stop", domain.ErrInvalidState)
+		}
+	case CommandAbandon:
+		if !isTimed(session) {
+			return domain.NewSessionError("abandon", domain.ErrInvalidState)
+		}
+		return sessionService.AbandonSession()
+	default:
+		return errUsage("unknown command " + req.Command)
+	}
+}
+
+// isTimed reports whether a work, break or flow session is running.
+func isTimed(session *domain.Session) bool {
+	switch session.GetState() {
+	case domain.WorkSession, domain.BreakSession, domain.FlowSession:
+		return true
+	default:
+		return false
+	}
+}
+
+func errUsage(detail string) error {
+	return fmt.Errorf("%w: %s", ErrUsage, detail)
+}
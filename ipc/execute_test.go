@@ -0,0 +1,54 @@
+package ipc
+
+import (
+	"testing"
+
+	"karedoro/application"
+	"karedoro/domain"
+)
+
+func TestExecute_SessionCommands(t *testing.T) {
+	sessionService := application.NewSessionService()
+	
+	resp := Execute(sessionService, Request{Command: CommandStart, Args: []string{"work"}})
+	if !resp.OK || resp.Status.State != domain.WorkSession.String() {
+		t.Fatalf("Expected a running work session, got %+v", resp)
+	}
+	
+	if resp := Execute(sessionService, Request{Command: CommandStart, Args: []string{"break"}}); resp.OK {
+		t.Error("Starting a session while one is running should fail")
+	}
+	
+	resp = Execute(sessionService, Request{Command: CommandPause})
+	if !resp.OK || !resp.Status.Paused {
+		t.Fatalf("Expected a paused session, got %+v", resp)
+	}
+	
+	resp = Execute(sessionService, Request{Command: CommandResume})
+	if !resp.OK || resp.Status.Paused {
+		t.Fatalf("Expected a resumed session, got %+v", resp)
+	}
+	
+	resp = Execute(sessionService, Request{Command: CommandAbandon})
+	if !resp.OK || resp.Status.State != domain.Idle.String() {
+		t.Fatalf("Expected Idle after abandoning, got %+v", resp)
+	}
+	
+	if resp := Execute(sessionService, Request{Command: CommandResume}); resp.OK {
+		t.Error("Resuming without a paused session should fail")
+	}
+}
+
+func TestExecute_Usage(t *testing.T) {
+	sessionService := application.NewSessionService()
+	
+	for _, req := range []Request{
+		{Command: "bogus"},
+		{Command: CommandStart},
+		{Command: CommandStart, Args: []string{"lunch"}},
+	} {
+		if resp := Execute(sessionService, req); resp.OK || resp.Error == "" {
+			t.Errorf("Expected an error for %+v, got %+v", req, resp)
+		}
+	}
+}
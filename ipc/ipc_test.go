@@ -0,0 +1,161 @@
+package ipc
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"karedoro/application"
+)
+
+func TestServer_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run", "karedoro.sock")
+	
+	server, err := Listen(path, func(req Request) Response {
+		if req.Command != CommandStart || len(req.Args) != 1 || req.Args[0] != "work" {
+			return Errorf("unexpected request %+v", req)
+		}
+		return Response{OK: true, Status: &application.Status{State: "WorkSession"}}
+	})
+	if err != nil {
+		t.Fatalf("Listen should not return error, got %v", err)
+	}
+	defer server.Close()
+	go server.Serve()
+	
+	resp, err := Call(path, Request{Command: CommandStart, Args: []string{"work"}})
+	if err != nil {
+		t.Fatalf("Call should not return error, got %v", err)
+	}
+	if resp.Status == nil || resp.Status.State != "WorkSession" {
+		t.Errorf("Expected WorkSession status, got %+v", resp.Status)
+	}
+	
+	if _, err := Call(path, Request{Command: CommandPause}); err == nil {
+		t.Error("Call should return the handler's error")
+	}
+}
+
+func TestListen_AlreadyRunning(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run", "karedoro.sock")
+	
+	server, err := Listen(path, func(Request) Response { return Response{OK: true} })
+	if err != nil {
+		t.Fatalf("Listen should not return error, got %v", err)
+	}
+	defer server.Close()
+	go server.Serve()
+	
+	if _, err := Listen(path, func(Request) Response { return Response{OK: true} }); !errors.Is(err, ErrAlreadyRunning) {
+		t.Errorf("Expected ErrAlreadyRunning, got %v", err)
+	}
+}
+
+func TestListen_RemovesStaleSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run", "karedoro.sock")
+	if err := MkdirPrivate(filepath.Dir(path)); err != nil {
+		t.Fatalf("MkdirPrivate should not return error, got %v", err)
+	}
+	
+	// ソケットファイルだけが残っている状態を作る
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("Failed to create socket: %v", err)
+	}
+	listener.(*net.UnixListener).SetUnlinkOnClose(false)
+	listener.Close()
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("Expected a stale socket file, got %v", err)
+	}
+	
+	server, err := Listen(path, func(Request) Response { return Response{OK: true} })
+	if err != nil {
+		t.Fatalf("Listen should replace a stale socket, got %v", err)
+	}
+	server.Close()
+}
+
+func TestCall_NotRunning(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run", "karedoro.sock")
+	
+	if _, err := Call(path, Request{Command: CommandStatus}); !errors.Is(err, ErrNotRunning) {
+		t.Errorf("Expected ErrNotRunning, got %v", err)
+	}
+}
+
+func TestAcquireLock(t *testing.T) {
+	path := LockPath(filepath.Join(t.TempDir(), "run", "karedoro.sock"))
+	
+	lock, err := AcquireLock(path)
+	if err != nil {
+		t.Fatalf("AcquireLock should not return error, got %v", err)
+	}
+	if pid := LockOwner(path); pid != os.Getpid() {
+		t.Errorf("Lock file should record pid %d, got %d", os.Getpid(), pid)
+	}
+	
+	if _, err := AcquireLock(path); !errors.Is(err, ErrAlreadyRunning) {
+		t.Errorf("Expected ErrAlreadyRunning, got %v", err)
+	}
+	
+	lock.Release()
+	
+	// A lock file left behind is taken over once nobody holds it
+	lock, err = AcquireLock(path)
+	if err != nil {
+		t.Fatalf("AcquireLock should succeed after release, got %v", err)
+	}
+	lock.Release()
+}
+
+func TestMkdirPrivate(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("ownership and mode bits are not checked on Windows")
+	}
+	base := t.TempDir()
+	
+	dir := filepath.Join(base, "fresh")
+	if err := MkdirPrivate(dir); err != nil {
+		t.Fatalf("A new directory should be accepted, got %v", err)
+	}
+	if err := MkdirPrivate(dir); err != nil {
+		t.Errorf("Our own directory should be accepted again, got %v", err)
+	}
+	
+	open := filepath.Join(base, "open")
+	if err := os.Mkdir(open, 0700); err != nil {
+		t.Fatal(err)
+	}
+	os.Chmod(open, 0777)
+	if err := MkdirPrivate(open); !errors.Is(err, ErrUnsafeRuntimeDir) {
+		t.Errorf("Expected a directory open to others to be refused, got %v", err)
+	}
+	
+	// 他のユーザーが先に作ったディレクトリ（root のときだけ作れる）
+	if os.Getuid() == 0 {
+		foreign := filepath.Join(base, "foreign")
+		if err := os.Mkdir(foreign, 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chown(foreign, 12345, 12345); err != nil {
+			t.Fatal(err)
+		}
+		if err := MkdirPrivate(foreign); !errors.Is(err, ErrUnsafeRuntimeDir) {
+			t.Errorf("Expected another user's directory to be refused, got %v", err)
+		}
+	}
+	
+	link := filepath.Join(base, "link")
+	if err := os.Symlink(dir, link); err != nil {
+		t.Fatal(err)
+	}
+	if err := MkdirPrivate(link); !errors.Is(err, ErrUnsafeRuntimeDir) {
+		t.Errorf("Expected a symlink to be refused, got %v", err)
+	}
+	if _, err := Listen(filepath.Join(link, "karedoro.sock"), nil); !errors.Is(err, ErrUnsafeRuntimeDir) {
+		t.Errorf("Expected Listen to refuse the symlink, got %v", err)
+	}
+}
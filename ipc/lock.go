@@ -0,0 +1,81 @@
+package ipc
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// errLocked is returned by lockFile when another process holds the lock.
+var errLocked = errors.New("lock is held by another process")
+
+// Lock is an exclusive hold on karedoro's lock file. The operating system
+// releases it when the process exits, so a lock file left behind by a crash
+// is simply taken over by the next instance.
+type Lock struct {
+	file *os.File
+}
+
+// LockPath returns the single-instance lock file that guards the socket at
+// socketPath.
+func LockPath(socketPath string) string {
+	return filepath.Join(filepath.Dir(socketPath), "karedoro.lock")
+}
+
+// SnapshotPath returns where the instance serving socketPath publishes its
+// state snapshot.
+func SnapshotPath(socketPath string) string {
+	return filepath.Join(filepath.Dir(socketPath), "state.json")
+}
+
+// AcquireLock takes the lock file at path and records the current PID in it.
+// It returns ErrAlreadyRunning if another process holds the lock.
+func AcquireLock(path string) (*Lock, error) {
+	if err := MkdirPrivate(filepath.Dir(path)); err != nil {
+		return nil, err
+	}
+	
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	if err := lockFile(file); err != nil {
+		file.Close()
+		if errors.Is(err, errLocked) {
+			return nil, ErrAlreadyRunning
+		}
+		return nil, fmt.Errorf("lock %s: %w", path, err)
+	}
+	
+	// 前回クラッシュしたプロセスのPIDが残っていれば上書きする
+	if err := file.Truncate(0); err == nil {
+		file.WriteAt([]byte(strconv.Itoa(os.Getpid())+"\n"), 0)
+	}
+	
+	return &Lock{file: file}, nil
+}
+
+// Release gives up the lock. The file itself is left in place so that a
+// process opening it concurrently always locks the same file.
+func (l *Lock) Release() error {
+	l.file.Truncate(0)
+	unlockFile(l.file)
+	return l.file.Close()
+}
+
+// LockOwner returns the PID recorded in the lock file at path, or 0 if it
+// cannot be read.
+func LockOwner(path string) int {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return 0
+	}
+	return pid
+}
@@ -0,0 +1,19 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd && !dragonfly && !windows
+
+package ipc
+
+import "os"
+
+// Without file locking the control socket is the only single-instance check.
+func lockFile(file *os.File) error {
+	return nil
+}
+
+func unlockFile(file *os.File) error {
+	return nil
+}
+
+// ProcessRunning cannot check for processes here and assumes pid is alive.
+func ProcessRunning(pid int) bool {
+	return true
+}
@@ -0,0 +1,64 @@
+// Package ipc implements the control protocol spoken over karedoro's Unix
+// socket: one JSON request and one JSON response per connection.
+package ipc
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"karedoro/application"
+)
+
+// Commands understood by the daemon.
+const (
+	CommandStart   = "start"
+	CommandPause   = "pause"
+	CommandResume  = "resume"
+	CommandStatus  = "status"
+	CommandAbandon = "abandon"
+	CommandPair    = "pair"
+	
+	// CommandStop ends a flow session or overtime, which have no fixed end.
+	CommandStop = "stop"
+	
+	// CommandRaise asks the running instance to bring its window to the
+	// front; a second launch sends it before exiting.
+	CommandRaise = "raise"
+)
+
+// Request asks the running instance to do something.
+type Request struct {
+	Command string   `json:"command"`
+	Args    []string `json:"args,omitempty"`
+}
+
+// Response carries the outcome of a request and the session status after it.
+type Response struct {
+	OK     bool                `json:"ok"`
+	Error  string              `json:"error,omitempty"`
+	Status *application.Status `json:"status,omitempty"`
+	
+	// PairingCode answers CommandPair.
+	PairingCode string `json:"pairing_code,omitempty"`
+}
+
+// Errorf builds a failed response.
+func Errorf(format string, args ...interface{}) Response {
+	return Response{Error: fmt.Sprintf(format, args...)}
+}
+
+// SocketPath returns where the control socket lives:
+// $XDG_RUNTIME_DIR/karedoro/karedoro.sock, or a per-user directory under the
+// system temp dir when XDG_RUNTIME_DIR is not set.
+func SocketPath() string {
+	return filepath.Join(RuntimeDir(), "karedoro.sock")
+}
+
+// RuntimeDir returns the per-user directory for karedoro's runtime files.
+func RuntimeDir() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "karedoro")
+	}
+	return filepath.Join(os.TempDir(), fmt.Sprintf("karedoro-%d", os.Getuid()))
+}
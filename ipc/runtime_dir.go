@@ -0,0 +1,31 @@
+package ipc
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ErrUnsafeRuntimeDir is returned for a runtime directory that another user
+// could have prepared.
+var ErrUnsafeRuntimeDir = errors.New("unsafe runtime directory")
+
+// MkdirPrivate creates dir for files only the current user may touch, such
+// as the control socket and the lock. Without XDG_RUNTIME_DIR the directory
+// lives at a predictable path in the shared temp dir, so another user could
+// create it first and then take over the socket; a directory that is a
+// symlink, belongs to someone else or is open to others is refused.
+func MkdirPrivate(dir string) error {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%w: %s is not a directory", ErrUnsafeRuntimeDir, dir)
+	}
+	return checkPrivate(dir, info)
+}
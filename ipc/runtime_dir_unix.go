@@ -0,0 +1,23 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package ipc
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+func checkPrivate(dir string, info os.FileInfo) error {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return fmt.Errorf("%w: cannot read the owner of %s", ErrUnsafeRuntimeDir, dir)
+	}
+	if int(stat.Uid) != os.Getuid() {
+		return fmt.Errorf("%w: %s belongs to user %d", ErrUnsafeRuntimeDir, dir, stat.Uid)
+	}
+	if perm := info.Mode().Perm(); perm != 0700 {
+		return fmt.Errorf("%w: %s has mode %04o, want 0700", ErrUnsafeRuntimeDir, dir, perm)
+	}
+	return nil
+}
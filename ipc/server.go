@@ -0,0 +1,95 @@
+package ipc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ErrAlreadyRunning is returned by Listen when another instance owns the socket.
+var ErrAlreadyRunning = errors.New("karedoro is already running")
+
+// connTimeout bounds how long a single client may take to send its request.
+const connTimeout = 5 * time.Second
+
+// Handler answers a single request.
+type Handler func(Request) Response
+
+// Server accepts control connections on a Unix socket.
+type Server struct {
+	path     string
+	listener net.Listener
+	handler  Handler
+}
+
+// Listen binds the socket at path. A socket left behind by a process that
+// is no longer running is removed first.
+func Listen(path string, handler Handler) (*Server, error) {
+	if err := MkdirPrivate(filepath.Dir(path)); err != nil {
+		return nil, err
+	}
+	
+	if _, err := os.Stat(path); err == nil {
+		if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+			conn.Close()
+			return nil, ErrAlreadyRunning
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("remove stale socket: %w", err)
+		}
+	}
+	
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	
+	return &Server{
+		path:     path,
+		listener: listener,
+		handler:  handler,
+	}, nil
+}
+
+// Serve handles connections until the server is closed.
+func (s *Server) Serve() error {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		go s.serveConn(conn)
+	}
+}
+
+// Close stops accepting connections and removes the socket.
+func (s *Server) Close() error {
+	err := s.listener.Close()
+	os.Remove(s.path)
+	return err
+}
+
+func (s *Server) serveConn(conn net.Conn) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(connTimeout))
+	
+	var req Request
+	var resp Response
+	if err := json.NewDecoder(conn).Decode(&req); err != nil {
+		resp = Errorf("invalid request: %v", err)
+	} else {
+		resp = s.handler(req)
+	}
+	
+	if err := json.NewEncoder(conn).Encode(resp); err != nil {
+		log.Printf("ipc: failed to write response: %v", err)
+	}
+}
@@ -1,19 +1,78 @@
+//go:build !ebitenui
+
 package main
 
 import (
+	"errors"
 	"log"
+	"os"
 
 	"karedoro/application"
+	"karedoro/cli"
+	"karedoro/dbusapi"
+	"karedoro/httpapi"
+	"karedoro/instance"
+	"karedoro/ipc"
 	"karedoro/presentation"
 )
 
 func main() {
+	// サブコマンドが指定された場合はウィンドウを開かずに処理する
+	action, launch := cli.ParseLaunch(os.Args[1:])
+	if !launch {
+		os.Exit(cli.Run(os.Args[1:]))
+	}
+	
+	// 既に起動している場合はそちらに処理を引き渡して終了する
+	inst, err := instance.Acquire(ipc.SocketPath())
+	if errors.Is(err, ipc.ErrAlreadyRunning) {
+		os.Exit(cli.HandOff(action))
+	}
+	if err != nil {
+		log.Printf("Warning: single-instance check disabled: %v", err)
+	} else {
+		defer inst.Close()
+	}
+	
 	// Build dependency graph
 	services := application.NewServices()
+	handler := instance.NewHandler(services.Loop)
+	if inst != nil {
+		if err := inst.Listen(ipc.SocketPath(), handler.Handle); err != nil {
+			log.Printf("Warning: control socket disabled: %v", err)
+		}
+		inst.Publish(services.Session)
+	}
+	services.AttachIntegrations()
+	
+	// ローカルAPIは設定で有効にした場合のみ起動する
+	api, err := httpapi.Start(services)
+	if err != nil {
+		log.Printf("Warning: http api disabled: %v", err)
+	}
+	
+	bus, err := dbusapi.Start(services)
+	if err != nil {
+		log.Printf("Warning: d-bus interface disabled: %v", err)
+	}
+	if bus != nil {
+		defer bus.Close()
+	}
 	
 	// Create and run the application
 	app := presentation.NewAppWithServices(services)
+	handler.SetRaise(app.Raise)
+	if api != nil {
+		defer api.Close()
+		app.SetPairingCode(api.PairingCode)
+		handler.SetPairingCode(api.PairingCode)
+	}
+	if action != nil {
+		if err := ipc.Apply(services.Session, *action); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}
 	if err := app.Run(); err != nil {
 		log.Fatal(err)
 	}
-}
\ No newline at end of file
+}
@@ -1,3 +1,5 @@
+//go:build ebitenui
+
 package main
 
 import (
@@ -15,6 +15,7 @@ type Screen int
 const (
 	MainScreen Screen = iota
 	FullscreenOverlay
+	SettingsScreen
 )
 
 type Button struct {
@@ -22,29 +23,40 @@ type Button struct {
 	Text       string
 	Action     func()
 	Hovered    bool
-}
-
-func NewApp() (*App, *application.AudioService) {
-	audioService := application.NewAudioService()
-	notificationService := application.NewNotificationService()
-	sessionService := application.NewSessionService()
-	configService := application.NewConfigService()
-	eventHandler := NewEventHandler(audioService, notificationService)
 	
-	coordinator := NewAppCoordinator(sessionService, configService, eventHandler)
-	coordinator.Initialize()
+	// Available reports whether the button can currently be pressed; nil means always.
+	Available func() bool
 	
-	app := &App{
-		coordinator: coordinator,
-	}
+	// Corner keeps the button in the top right corner instead of stacking it.
+	Corner bool
 	
-	return app, audioService
+	// Shortcut is the keymap action that presses the button; empty for none.
+	Shortcut string
+}
+
+func (b *Button) IsAvailable() bool {
+	return b.Available == nil || b.Available()
+}
+
+// NewApp creates an App with the services wired up by application.NewServices.
+func NewApp() (*App, *application.AudioService) {
+	services := application.NewServices()
+	return NewAppWithServices(services), services.Audio.(*application.AudioService)
 }
 
 // NewAppWithServices creates a new App with dependency injection.
 func NewAppWithServices(services *application.Services) *App {
 	eventHandler := NewEventHandler(services.Audio, services.Notification)
-	coordinator := NewAppCoordinator(services.Session, services.Config, eventHandler)
+	if services.Metrics != nil {
+		eventHandler.feedback.SetErrorHandler(services.Metrics.RecordFailure)
+	}
+	coordinator := NewAppCoordinator(services.Session, services.Config, services.Stats, eventHandler)
+	coordinator.loop = services.Loop
+	coordinator.settingsEditor.save = services.ApplyConfig
+	if services.Messages != nil {
+		coordinator.uiManager.SetMessages(services.Messages)
+	}
+	coordinator.uiManager.SetFonts(loadConfiguredFonts(services.Config))
 	coordinator.Initialize()
 	
 	return &App{
@@ -55,6 +67,19 @@ func NewAppWithServices(services *application.Services) *App {
 
 
 
+// SetPairingCode shows the web UI pairing code on the idle screen.
+func (a *App) SetPairingCode(code func() string) {
+	a.coordinator.uiManager.GetScreenRenderer().SetPairingCode(code)
+}
+
+// Raise brings the window to the front, e.g. when karedoro is launched again.
+func (a *App) Raise() {
+	if ebiten.IsWindowMinimized() {
+		ebiten.RestoreWindow()
+	}
+	ebiten.RequestAttention()
+}
+
 func (a *App) Update() error {
 	return a.coordinator.Update()
 }
@@ -66,6 +91,11 @@ func (a *App) Draw(screen *ebiten.Image) {
 
 
 func (a *App) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int) {
+	// The mini timer is smaller than the minimum size
+	if a.coordinator.mini.active {
+		return outsideWidth, outsideHeight
+	}
+	
 	// Allow dynamic resizing but set minimum size
 	if outsideWidth < MinWindowWidth {
 		outsideWidth = MinWindowWidth
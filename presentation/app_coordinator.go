@@ -2,6 +2,7 @@ package presentation
 
 import (
 	"fmt"
+	"log"
 	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
@@ -13,20 +14,39 @@ import (
 type AppCoordinator struct {
 	sessionService *application.SessionService
 	configService  *application.ConfigService
+	statsService   *application.StatsService
 	eventHandler   *EventHandler
 	uiManager      *UIManager
 	inputHandler   *InputHandler
+	keymap         *Keymap
+	settingsEditor *SettingsEditor
+	themes         *themeSelector
+	mini           *miniMode
+	
+	// loop runs calls queued from other goroutines; nil if there are none.
+	loop *application.Loop
 }
 
-func NewAppCoordinator(sessionService *application.SessionService, configService *application.ConfigService, eventHandler *EventHandler) *AppCoordinator {
+func NewAppCoordinator(sessionService *application.SessionService, configService *application.ConfigService, statsService *application.StatsService, eventHandler *EventHandler) *AppCoordinator {
+	settingsEditor := NewSettingsEditor(configService, eventHandler.audioService)
+	keymap := NewKeymap(configService.GetConfig().Keys)
 	coordinator := &AppCoordinator{
 		sessionService: sessionService,
 		configService:  configService,
+		statsService:   statsService,
 		eventHandler:   eventHandler,
-		uiManager:      NewUIManager(),
-		inputHandler:   NewInputHandler(sessionService),
+		uiManager:      NewUIManager(settingsEditor),
+		inputHandler:   NewInputHandler(sessionService, keymap),
+		keymap:         keymap,
+		settingsEditor: settingsEditor,
+		themes:         newThemeSelector(configService),
+		mini:           newMiniMode(),
 	}
 	
+	settingsEditor.onClose = coordinator.closeSettings
+	coordinator.uiManager.GetButtonManager().SetSettingsAction(coordinator.openSettings)
+	coordinator.uiManager.SetKeymap(keymap)
+	coordinator.uiManager.SetTheme(coordinator.themes.current)
 	coordinator.setupEventCallbacks()
 	
 	return coordinator
@@ -35,12 +55,7 @@ func NewAppCoordinator(sessionService *application.SessionService, configService
 func (ac *AppCoordinator) setupEventCallbacks() {
 	ac.eventHandler.SetupCallbacks(
 		ac.sessionService,
-		func() {
-			ac.uiManager.SetCurrentScreen(FullscreenOverlay)
-			ac.uiManager.SetFullscreen(true)
-			screenWidth, screenHeight := ebiten.WindowSize()
-			ac.uiManager.SetupEndOfWorkButtons(screenWidth, screenHeight, ac.sessionService)
-		},
+		ac.showEndOfWorkOverlay,
 		func() {
 			ac.uiManager.SetCurrentScreen(FullscreenOverlay)
 			ac.uiManager.SetFullscreen(true)
@@ -49,8 +64,45 @@ func (ac *AppCoordinator) setupEventCallbacks() {
 		},
 	)
 	
+	// Escalated warnings re-assert the overlay; the last step also flashes it
+	ac.sessionService.AddEventCallback(domain.EventWarningUrgent, func() {
+		ac.uiManager.SetCurrentScreen(FullscreenOverlay)
+		ac.uiManager.SetFullscreen(true)
+	})
+	
+	ac.sessionService.AddEventCallback(domain.EventWarningCritical, func() {
+		ac.uiManager.SetCurrentScreen(FullscreenOverlay)
+		ac.uiManager.SetFullscreen(true)
+		ac.uiManager.SetFlashing(true)
+	})
+	
+	// Overtime runs on the main screen; when it ends without a break the overlay returns
+	ac.sessionService.AddEventCallback(domain.EventOvertimeStart, func() {
+		ac.uiManager.SetFlashing(false)
+		ac.uiManager.SetCurrentScreen(MainScreen)
+		if ac.uiManager.IsFullscreen() {
+			ebiten.SetFullscreen(false)
+			ac.uiManager.SetFullscreen(false)
+		}
+	})
+	
+	ac.sessionService.AddEventCallback(domain.EventOvertimeEnd, func() {
+		if ac.sessionService.GetSession().GetState() == domain.Idle {
+			ebiten.SetFullscreen(true)
+			ac.showEndOfWorkOverlay()
+		}
+	})
+	
+	// An abandoned session returns to the main screen rather than the overlay
+	ac.sessionService.AddEventCallback(domain.EventSessionAbandon, func() {
+		ac.uiManager.SetCurrentScreen(MainScreen)
+		screenWidth, screenHeight := ebiten.WindowSize()
+		ac.uiManager.SetupMainButtons(screenWidth, screenHeight, ac.sessionService)
+	})
+	
 	// Handle session start events that need screen changes
 	ac.sessionService.AddEventCallback(domain.EventWorkSessionStart, func() {
+		ac.uiManager.SetFlashing(false)
 		ac.uiManager.SetCurrentScreen(MainScreen)
 		if ac.uiManager.IsFullscreen() {
 			ebiten.SetFullscreen(false)
@@ -59,6 +111,16 @@ func (ac *AppCoordinator) setupEventCallbacks() {
 	})
 	
 	ac.sessionService.AddEventCallback(domain.EventBreakSessionStart, func() {
+		ac.uiManager.SetFlashing(false)
+		ac.uiManager.SetCurrentScreen(MainScreen)
+		if ac.uiManager.IsFullscreen() {
+			ebiten.SetFullscreen(false)
+			ac.uiManager.SetFullscreen(false)
+		}
+	})
+	
+	ac.sessionService.AddEventCallback(domain.EventFlowSessionStart, func() {
+		ac.uiManager.SetFlashing(false)
 		ac.uiManager.SetCurrentScreen(MainScreen)
 		if ac.uiManager.IsFullscreen() {
 			ebiten.SetFullscreen(false)
@@ -67,6 +129,28 @@ func (ac *AppCoordinator) setupEventCallbacks() {
 	})
 }
 
+func (ac *AppCoordinator) showEndOfWorkOverlay() {
+	ac.uiManager.SetCurrentScreen(FullscreenOverlay)
+	ac.uiManager.SetFullscreen(true)
+	screenWidth, screenHeight := ebiten.WindowSize()
+	ac.uiManager.SetupEndOfWorkButtons(screenWidth, screenHeight, ac.sessionService)
+}
+
+// openSettings shows the settings screen; only the idle main screen has it.
+func (ac *AppCoordinator) openSettings() {
+	if ac.uiManager.GetCurrentScreen() != MainScreen || ac.sessionService.GetSession().GetState() != domain.Idle {
+		return
+	}
+	ac.settingsEditor.Open()
+	ac.uiManager.SetCurrentScreen(SettingsScreen)
+}
+
+func (ac *AppCoordinator) closeSettings() {
+	ac.uiManager.SetCurrentScreen(MainScreen)
+	screenWidth, screenHeight := ebiten.WindowSize()
+	ac.uiManager.SetupMainButtons(screenWidth, screenHeight, ac.sessionService)
+}
+
 func (ac *AppCoordinator) Initialize() {
 	// Setup initial buttons
 	screenWidth, screenHeight := ebiten.WindowSize()
@@ -74,19 +158,71 @@ func (ac *AppCoordinator) Initialize() {
 }
 
 func (ac *AppCoordinator) Update() error {
+	// 外部（API等）からの操作はゲームループ上で実行する
+	if ac.loop != nil {
+		ac.loop.RunPending()
+	}
 	ac.sessionService.Update()
-	ac.inputHandler.HandleInput()
 	
-	// Update button positions and handle interactions
+	// 全画面オーバーレイなどが出るときはミニモードから元の大きさに戻す
+	if ac.mini.active && ac.uiManager.GetCurrentScreen() != MainScreen {
+		ac.mini.exit()
+	}
+	
+	// 設定画面では入力をすべて設定画面が受け取る
 	screenWidth, screenHeight := ebiten.WindowSize()
+	if ac.uiManager.GetCurrentScreen() == SettingsScreen {
+		ac.settingsEditor.Update(screenWidth, screenHeight)
+		ac.updateTheme()
+		return nil
+	}
+	
+	ac.inputHandler.HandleInput()
+	if ac.keymap.Pressed(application.ActionMiniMode) && ac.uiManager.GetCurrentScreen() == MainScreen && !ac.uiManager.IsFullscreen() {
+		ac.mini.toggle()
+	}
+	if ac.keymap.Pressed(application.ActionStats) && ac.uiManager.GetCurrentScreen() == MainScreen {
+		ac.uiManager.ToggleStats()
+	}
+	
+	// T で組み込みテーマを切り替える
+	if ac.keymap.Pressed(application.ActionTheme) {
+		if err := ac.themes.cycle(); err != nil {
+			log.Printf("Failed to save the theme: %v", err)
+		}
+	}
+	ac.updateTheme()
+	
+	// ミニモードではボタンを出さず、ドラッグで移動する
+	if ac.mini.active {
+		ac.mini.update()
+		return nil
+	}
+	
+	// Update button positions and handle interactions; hidden buttons
+	// cannot be pressed
 	ac.uiManager.UpdateButtonPositions(screenWidth, screenHeight)
-	ac.uiManager.UpdateButtons()
+	if ac.uiManager.ButtonsShown(ac.sessionService.GetSession()) {
+		ac.uiManager.UpdateButtons()
+	}
 	
 	return nil
 }
 
+// updateTheme applies a theme changed in the config, by T or through the API,
+// or by the time of day.
+func (ac *AppCoordinator) updateTheme() {
+	if ac.themes.update(time.Now()) {
+		ac.uiManager.SetTheme(ac.themes.current)
+	}
+}
+
 func (ac *AppCoordinator) Draw(screen *ebiten.Image) {
-	ac.uiManager.Draw(screen, ac.sessionService.GetSession())
+	if ac.mini.active {
+		ac.uiManager.DrawMiniTimer(screen, ac.sessionService.GetSession())
+		return
+	}
+	ac.uiManager.Draw(screen, ac.sessionService.GetSession(), ac.statsService.Today())
 }
 
 func (ac *AppCoordinator) RunSetup(audioService *application.AudioService) error {
@@ -1,31 +1,84 @@
 package presentation
 
 import (
+	"image/color"
+
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
 
 	"karedoro/application"
+	"karedoro/domain"
+	"karedoro/i18n"
+	"karedoro/theme"
 )
 
 type ButtonManager struct {
-	buttons []Button
+	buttons  []Button
+	messages *i18n.Catalog
+	
+	// openSettings adds a settings button to the main screen when set.
+	openSettings func()
+	
+	// keymap presses buttons by their shortcuts. focus is the button chosen
+	// with TAB or the arrow keys, -1 until one is chosen so that ENTER typed
+	// into another window cannot press a button on the overlay.
+	keymap *Keymap
+	focus  int
+	
+	fonts    *Fonts
+	theme    *theme.Theme
 }
 
 func NewButtonManager() *ButtonManager {
 	return &ButtonManager{
-		buttons: make([]Button, 0),
+		buttons:  make([]Button, 0),
+		messages: i18n.Default(),
+		keymap:   DefaultKeymap(),
+		focus:    -1,
+		fonts:    DefaultFonts(),
+		theme:    theme.Default(),
 	}
 }
 
+// SetMessages labels the buttons from messages.
+func (bm *ButtonManager) SetMessages(messages *i18n.Catalog) {
+	bm.messages = messages
+}
+
+// SetFonts draws the button labels with fonts.
+func (bm *ButtonManager) SetFonts(fonts *Fonts) {
+	bm.fonts = fonts
+}
+
+// SetSettingsAction adds a button that calls open to the main screen.
+func (bm *ButtonManager) SetSettingsAction(open func()) {
+	bm.openSettings = open
+}
+
+// SetKeymap presses the buttons with the keys of keymap.
+func (bm *ButtonManager) SetKeymap(keymap *Keymap) {
+	bm.keymap = keymap
+}
+
+// SetTheme draws the buttons in the colours of t.
+func (bm *ButtonManager) SetTheme(t *theme.Theme) {
+	bm.theme = t
+}
+
+func (bm *ButtonManager) text(key string) string {
+	return bm.messages.Text(key, i18n.Data{})
+}
+
 func (bm *ButtonManager) SetupMainButtons(screenWidth, screenHeight int, sessionService *application.SessionService) {
+	bm.focus = -1
 	bm.buttons = []Button{
 		{
 			X: screenWidth/2 - ButtonWidth/2,
 			Y: screenHeight/2 - ButtonHeight - ButtonPadding,
 			W: ButtonWidth,
 			H: ButtonHeight,
-			Text: StartWorkButtonText,
+			Text: bm.text("button.start_work"),
+			Shortcut: application.ActionStartWork,
 			Action: func() {
 				sessionService.StartWorkSession()
 			},
@@ -35,74 +88,151 @@ func (bm *ButtonManager) SetupMainButtons(screenWidth, screenHeight int, session
 			Y: screenHeight/2 + ButtonPadding,
 			W: ButtonWidth,
 			H: ButtonHeight,
-			Text: StartBreakButtonText,
+			Text: bm.text("button.start_break"),
+			Shortcut: application.ActionStartBreak,
 			Action: func() {
 				sessionService.StartBreakSession()
 			},
 		},
+		{
+			X: screenWidth/2 - ButtonWidth/2,
+			Y: screenHeight/2 + ButtonHeight + 3*ButtonPadding,
+			W: ButtonWidth,
+			H: ButtonHeight,
+			Text: bm.text("button.start_flow"),
+			Shortcut: application.ActionStartFlow,
+			Action: func() {
+				sessionService.StartFlowSession()
+			},
+		},
+	}
+	
+	if bm.openSettings != nil {
+		bm.buttons = append(bm.buttons, Button{
+			W:      SettingsButtonWidth,
+			H:      SettingsButtonHeight,
+			Text:     bm.text("button.settings"),
+			Action:   bm.openSettings,
+			Corner:   true,
+			Shortcut: application.ActionSettings,
+		})
 	}
 }
 
 func (bm *ButtonManager) SetupEndOfWorkButtons(screenWidth, screenHeight int, sessionService *application.SessionService) {
+	bm.focus = -1
 	bm.buttons = []Button{
 		{
 			X: screenWidth/2 - ButtonWidth/2,
 			Y: screenHeight/2 - ButtonHeight - ButtonPadding,
 			W: ButtonWidth,
 			H: ButtonHeight,
-			Text: StartBreakButtonText,
+			Text: bm.text("button.start_break"),
+			Shortcut: application.ActionStartBreak,
 			Action: func() {
 				sessionService.StartBreakSession()
 			},
 		},
-		{
+	}
+	
+	// 残業が許可されていれば「続ける」ボタンを出す
+	session := sessionService.GetSession()
+	if session.CanContinueOvertime() {
+		bm.buttons = append(bm.buttons, Button{
 			X: screenWidth/2 - ButtonWidth/2,
 			Y: screenHeight/2 + ButtonPadding,
 			W: ButtonWidth,
 			H: ButtonHeight,
-			Text: SkipBreakButtonText,
+			Text: bm.text("button.keep_going"),
+			Shortcut: application.ActionKeepGoing,
 			Action: func() {
-				sessionService.StartWorkSession()
+				sessionService.ContinueOvertime()
 			},
-		},
+		})
+	}
+	
+	// スキップ枠を使い切り、クールダウンもない場合はスキップボタンを出さない
+	if session.CanSkipBreak() || session.GetSkipPolicy().Cooldown > 0 {
+		bm.buttons = append(bm.buttons, Button{
+			X: screenWidth/2 - ButtonWidth/2,
+			Y: screenHeight/2 + ButtonPadding,
+			W: ButtonWidth,
+			H: ButtonHeight,
+			Text: bm.text("button.skip_break"),
+			Shortcut: application.ActionSkipBreak,
+			Action: func() {
+				sessionService.SkipBreak()
+			},
+			Available: session.CanSkipBreak,
+		})
 	}
 }
 
 func (bm *ButtonManager) SetupEndOfBreakButtons(screenWidth, screenHeight int, sessionService *application.SessionService) {
+	bm.focus = -1
 	bm.buttons = []Button{
 		{
 			X: screenWidth/2 - ButtonWidth/2,
 			Y: screenHeight/2,
 			W: ButtonWidth,
 			H: ButtonHeight,
-			Text: StartWorkButtonText,
+			Text: bm.text("button.start_work"),
+			Shortcut: application.ActionStartWork,
 			Action: func() {
 				sessionService.StartWorkSession()
 			},
 		},
 	}
+	
+	// フロータイムのサイクル中は次のフローも選べるようにする
+	if sessionService.GetSession().NextSessionType() == domain.Flow {
+		bm.buttons = append(bm.buttons, Button{
+			X: screenWidth/2 - ButtonWidth/2,
+			Y: screenHeight/2 + ButtonHeight + 2*ButtonPadding,
+			W: ButtonWidth,
+			H: ButtonHeight,
+			Text: bm.text("button.start_flow"),
+			Shortcut: application.ActionStartFlow,
+			Action: func() {
+				sessionService.StartFlowSession()
+			},
+		})
+	}
 }
 
 func (bm *ButtonManager) UpdateButtonPositions(screenWidth, screenHeight int) {
+	stacked := 0
+	for i := range bm.buttons {
+		if !bm.buttons[i].Corner {
+			stacked++
+		}
+	}
+	
 	// Update button positions based on current screen size
+	row := 0
 	for i := range bm.buttons {
-		switch len(bm.buttons) {
+		if bm.buttons[i].Corner {
+			bm.buttons[i].X = screenWidth - bm.buttons[i].W - 2*ButtonPadding
+			bm.buttons[i].Y = 2 * ButtonPadding
+			continue
+		}
+		switch stacked {
 		case 1: // End of break (single button)
 			bm.buttons[i].X = screenWidth/2 - ButtonWidth/2
 			bm.buttons[i].Y = screenHeight/2
-		case 2: // Main screen or end of work (two buttons)
-			if i == 0 {
-				bm.buttons[i].X = screenWidth/2 - ButtonWidth/2
-				bm.buttons[i].Y = screenHeight/2 - ButtonHeight - ButtonPadding
-			} else {
-				bm.buttons[i].X = screenWidth/2 - ButtonWidth/2
-				bm.buttons[i].Y = screenHeight/2 + ButtonPadding
-			}
+		default: // Main screen or end of work (two or more buttons, stacked)
+			bm.buttons[i].X = screenWidth/2 - ButtonWidth/2
+			bm.buttons[i].Y = screenHeight/2 - ButtonHeight - ButtonPadding + row*(ButtonHeight+2*ButtonPadding)
 		}
+		row++
 	}
 }
 
 func (bm *ButtonManager) UpdateButtons() {
+	if bm.updateKeys() {
+		return
+	}
+	
 	mx, my := ebiten.CursorPosition()
 	
 	for i := range bm.buttons {
@@ -110,32 +240,130 @@ func (bm *ButtonManager) UpdateButtons() {
 		button.Hovered = mx >= button.X && mx < button.X+button.W &&
 			my >= button.Y && my < button.Y+button.H
 		
-		if button.Hovered && inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		if button.Hovered && button.IsAvailable() && inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
 			button.Action()
 		}
 	}
 }
 
+// updateKeys presses a button by its shortcut, or moves the focus with TAB
+// and the arrow keys and presses the focused button with ENTER or SPACE. It
+// reports whether a button was pressed, as the buttons may since have been
+// replaced.
+func (bm *ButtonManager) updateKeys() bool {
+	for i := range bm.buttons {
+		button := &bm.buttons[i]
+		if button.Shortcut != "" && button.IsAvailable() && bm.keymap.Pressed(button.Shortcut) {
+			button.Action()
+			return true
+		}
+	}
+	
+	backward := ebiten.IsKeyPressed(ebiten.KeyShift)
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyDown), inpututil.IsKeyJustPressed(ebiten.KeyRight),
+		inpututil.IsKeyJustPressed(ebiten.KeyTab) && !backward:
+		bm.moveFocus(1)
+	case inpututil.IsKeyJustPressed(ebiten.KeyUp), inpututil.IsKeyJustPressed(ebiten.KeyLeft),
+		inpututil.IsKeyJustPressed(ebiten.KeyTab) && backward:
+		bm.moveFocus(-1)
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnter), inpututil.IsKeyJustPressed(ebiten.KeyKPEnter),
+		inpututil.IsKeyJustPressed(ebiten.KeySpace):
+		if bm.focus >= 0 && bm.focus < len(bm.buttons) && bm.buttons[bm.focus].IsAvailable() {
+			bm.buttons[bm.focus].Action()
+			return true
+		}
+	}
+	return false
+}
+
+// moveFocus moves the focus by step to the next button that can be pressed;
+// the first move from no focus lands on the first or last button.
+func (bm *ButtonManager) moveFocus(step int) {
+	count := len(bm.buttons)
+	focus := bm.focus
+	if focus < 0 && step < 0 {
+		focus = 0
+	}
+	for range bm.buttons {
+		focus = (focus + step + count) % count
+		if bm.buttons[focus].IsAvailable() {
+			bm.focus = focus
+			return
+		}
+	}
+}
+
 func (bm *ButtonManager) DrawButtons(screen *ebiten.Image) {
+	for i := range bm.buttons {
+		button := &bm.buttons[i]
+		drawButton(screen, button, bm.fonts, bm.theme)
+		if i == bm.focus {
+			drawFocusRing(screen, button, bm.theme.Text)
+		}
+		bm.drawShortcut(screen, button)
+	}
+}
+
+// drawShortcut shows the key of a button beside it: to the right of the
+// stacked buttons and to the left of the corner ones.
+func (bm *ButtonManager) drawShortcut(screen *ebiten.Image, button *Button) {
+	label := bm.keymap.Label(button.Shortcut)
+	if button.Shortcut == "" || label == "" {
+		return
+	}
+	
+	x := button.X + button.W + 2*ButtonPadding
+	if button.Corner {
+		x = button.X - ButtonPadding - textWidth(label, bm.fonts.Button)
+	}
+	y := button.Y + (button.H-lineHeight(bm.fonts.Button))/2
+	drawTextAt(screen, label, bm.fonts.Button, x, y, bm.theme.MutedText)
+}
+
+// drawFocusRing outlines the button that has the keyboard focus.
+func drawFocusRing(screen *ebiten.Image, button *Button, c color.Color) {
+	drawBorder(screen, button.X-2*FocusRingWidth, button.Y-2*FocusRingWidth, button.W+4*FocusRingWidth, button.H+4*FocusRingWidth, c, FocusRingWidth)
+}
+
+func drawButton(screen *ebiten.Image, button *Button, fonts *Fonts, t *theme.Theme) {
+	// Draw button shadow
+	drawRect(screen, button.X+ButtonShadowOffset, button.Y+ButtonShadowOffset, button.W, button.H, t.ButtonShadow)
+	
+	// Draw button background
+	buttonColor := t.Button
+	if !button.IsAvailable() {
+		buttonColor = t.ButtonDisabled
+	} else if button.Hovered {
+		buttonColor = t.ButtonHover
+	}
+	drawRect(screen, button.X, button.Y, button.W, button.H, buttonColor)
+	
+	// Draw button border
+	drawBorder(screen, button.X, button.Y, button.W, button.H, t.ButtonBorder, ButtonBorderWidth)
+	
+	// Draw button text (centered)
+	textY := button.Y + (button.H-lineHeight(fonts.Button))/2
+	drawCenteredText(screen, button.Text, fonts.Button, button.X+button.W/2, textY, t.ButtonText)
+}
+
+// Bounds returns the top of the highest button and the bottom of the lowest,
+// so that text can be laid out around them.
+func (bm *ButtonManager) Bounds() (top, bottom int) {
+	first := true
 	for _, button := range bm.buttons {
-		// Draw button shadow
-		drawRect(screen, button.X+ButtonShadowOffset, button.Y+ButtonShadowOffset, button.W, button.H, ButtonShadow)
-		
-		// Draw button background
-		buttonColor := ButtonColor
-		if button.Hovered {
-			buttonColor = ButtonHoverColor
+		if button.Corner {
+			continue
 		}
-		drawRect(screen, button.X, button.Y, button.W, button.H, buttonColor)
-		
-		// Draw button border
-		drawBorder(screen, button.X, button.Y, button.W, button.H, GrayBorder, ButtonBorderWidth)
-		
-		// Draw button text (centered)
-		textX := button.X + button.W/2 - len(button.Text)*TextCharWidth
-		textY := button.Y + button.H/2 - TextCharHeight
-		ebitenutil.DebugPrintAt(screen, button.Text, textX, textY)
+		if first || button.Y < top {
+			top = button.Y
+		}
+		if first || button.Y+button.H > bottom {
+			bottom = button.Y + button.H
+		}
+		first = false
 	}
+	return top, bottom
 }
 
 func (bm *ButtonManager) GetButtons() []Button {
@@ -2,15 +2,13 @@ package presentation
 
 import (
 	"fmt"
-	"image/color"
 	"log"
+	"time"
 
 	"github.com/ebitenui/ebitenui"
 	"github.com/ebitenui/ebitenui/widget"
 	"github.com/ebitenui/ebitenui/image"
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/text"
-	"golang.org/x/image/font/basicfont"
 
 	"karedoro/application"
 	"karedoro/domain"
@@ -23,6 +21,9 @@ type EbitenUIApp struct {
 	audioService   domain.AudioPlayer
 	buttonContainer *widget.Container
 	progressBar    *widget.ProgressBar
+	loop           *application.Loop
+	fonts          *Fonts
+	themes         *themeSelector
 	
 	// ボタンラベル追跡用
 	buttonLabels   map[*widget.Button]string
@@ -33,10 +34,29 @@ func NewEbitenUIApp(services *application.Services) *EbitenUIApp {
 	app := &EbitenUIApp{
 		sessionService: services.Session,
 		audioService:   services.Audio,
+		loop:           services.Loop,
+		fonts:          loadConfiguredFonts(services.Config),
+		themes:         newThemeSelector(services.Config),
 		buttonLabels:   make(map[*widget.Button]string),
 	}
 	
 	app.buildUI()
+	
+	// 自動再開・中断などセッション側の変化でもボタンを更新する
+	for _, event := range []string{
+		domain.EventSessionResume,
+		domain.EventSessionAbandon,
+		domain.EventWorkSessionEnd,
+		domain.EventBreakSessionEnd,
+		domain.EventWorkSessionStart,
+		domain.EventBreakSessionStart,
+		domain.EventOvertimeStart,
+		domain.EventOvertimeEnd,
+		domain.EventFlowSessionStart,
+		domain.EventFlowSessionEnd,
+	} {
+		services.Session.AddEventCallback(event, app.updateButtons)
+	}
 	return app
 }
 
@@ -61,12 +81,12 @@ func (a *EbitenUIApp) buildUI() {
 		),
 		widget.ProgressBarOpts.Images(
 			&widget.ProgressBarImage{
-				Idle:  image.NewNineSliceColor(color.RGBA{60, 60, 60, 255}),
-				Hover: image.NewNineSliceColor(color.RGBA{80, 80, 80, 255}),
+				Idle:  image.NewNineSliceColor(a.themes.current.ProgressBackground),
+				Hover: image.NewNineSliceColor(a.themes.current.ProgressBackground),
 			},
 			&widget.ProgressBarImage{
-				Idle:  image.NewNineSliceColor(color.RGBA{0, 180, 0, 255}),
-				Hover: image.NewNineSliceColor(color.RGBA{0, 200, 0, 255}),
+				Idle:  image.NewNineSliceColor(a.themes.current.Progress),
+				Hover: image.NewNineSliceColor(a.themes.current.Progress),
 			},
 		),
 		widget.ProgressBarOpts.Values(0, 1500, 0), // 25分 = 1500秒
@@ -115,9 +135,9 @@ func (a *EbitenUIApp) createButton(buttonText string, clickHandler func()) *widg
 			widget.WidgetOpts.MinSize(250, 60),
 		),
 		widget.ButtonOpts.Image(&widget.ButtonImage{
-			Idle:    image.NewNineSliceColor(color.RGBA{70, 130, 180, 255}),  // 鋼鉄青
-			Hover:   image.NewNineSliceColor(color.RGBA{100, 149, 237, 255}), // コーンフラワー青
-			Pressed: image.NewNineSliceColor(color.RGBA{65, 105, 225, 255}),  // 王室青
+			Idle:    image.NewNineSliceColor(a.themes.current.Button),
+			Hover:   image.NewNineSliceColor(a.themes.current.ButtonHover),
+			Pressed: image.NewNineSliceColor(a.themes.current.ButtonPressed),
 		}),
 		widget.ButtonOpts.ClickedHandler(func(args *widget.ButtonClickedEventArgs) {
 			log.Printf("Button clicked: %s", buttonText)
@@ -159,7 +179,7 @@ func (a *EbitenUIApp) updateButtons() {
 				a.updateButtons()
 			})
 			a.buttonContainer.AddChild(resumeBtn)
-		} else {
+		} else if session.CanPause() {
 			pauseBtn := a.createButton("Pause Work", func() {
 				log.Printf("Pause Work clicked")
 				err := a.sessionService.PauseSession()
@@ -171,6 +191,7 @@ func (a *EbitenUIApp) updateButtons() {
 			})
 			a.buttonContainer.AddChild(pauseBtn)
 		}
+		a.addExtendButton(session)
 	case domain.BreakSession:
 		if isPaused {
 			resumeBtn := a.createButton("Resume Break", func() {
@@ -183,7 +204,7 @@ func (a *EbitenUIApp) updateButtons() {
 				a.updateButtons()
 			})
 			a.buttonContainer.AddChild(resumeBtn)
-		} else {
+		} else if session.CanPause() {
 			pauseBtn := a.createButton("Pause Break", func() {
 				log.Printf("Pause Break clicked")
 				err := a.sessionService.PauseSession()
@@ -195,11 +216,57 @@ func (a *EbitenUIApp) updateButtons() {
 			})
 			a.buttonContainer.AddChild(pauseBtn)
 		}
-	case domain.Idle:
-		sessionType := session.GetSessionType()
+		a.addExtendButton(session)
+	case domain.FlowSession:
+		stopBtn := a.createButton("Stop Flow -> Break", func() {
+			log.Printf("Stop Flow clicked")
+			err := a.sessionService.StopFlowSession()
+			if err != nil {
+				log.Printf("Failed to stop flow session: %v", err)
+				return
+			}
+			a.updateButtons()
+		})
+		a.buttonContainer.AddChild(stopBtn)
 		
-		if sessionType == domain.Work {
-			// 作業セッション終了後
+		if isPaused {
+			resumeBtn := a.createButton("Resume Flow", func() {
+				log.Printf("Resume Flow clicked")
+				err := a.sessionService.ResumeSession()
+				if err != nil {
+					log.Printf("Failed to resume session: %v", err)
+					return
+				}
+				a.updateButtons()
+			})
+			a.buttonContainer.AddChild(resumeBtn)
+		} else if session.CanPause() {
+			pauseBtn := a.createButton("Pause Flow", func() {
+				log.Printf("Pause Flow clicked")
+				err := a.sessionService.PauseSession()
+				if err != nil {
+					log.Printf("Failed to pause session: %v", err)
+					return
+				}
+				a.updateButtons()
+			})
+			a.buttonContainer.AddChild(pauseBtn)
+		}
+	case domain.Overtime:
+		breakBtn := a.createButton("Finish -> Break", func() {
+			log.Printf("Finish -> Break clicked")
+			err := a.sessionService.StartBreakSession()
+			if err != nil {
+				log.Printf("Failed to start break session: %v", err)
+				return
+			}
+			a.audioService.PlayStartSound()
+			a.updateButtons()
+		})
+		a.buttonContainer.AddChild(breakBtn)
+	case domain.Idle:
+		if session.IsBreakDue() {
+			// 作業セッション（またはフロー）終了後
 			startBreakBtn := a.createButton("Start Break", func() {
 				log.Printf("Start Break clicked")
 				err := a.sessionService.StartBreakSession()
@@ -212,17 +279,37 @@ func (a *EbitenUIApp) updateButtons() {
 			})
 			a.buttonContainer.AddChild(startBreakBtn)
 			
-			skipBreakBtn := a.createButton("Skip Break", func() {
-				log.Printf("Skip Break clicked")
-				err := a.sessionService.StartWorkSession()
-				if err != nil {
-					log.Printf("Failed to start work session: %v", err)
-					return
+			if session.CanContinueOvertime() {
+				keepGoingBtn := a.createButton("Keep Going", func() {
+					log.Printf("Keep Going clicked")
+					err := a.sessionService.ContinueOvertime()
+					if err != nil {
+						log.Printf("Failed to continue overtime: %v", err)
+						return
+					}
+					a.updateButtons()
+				})
+				a.buttonContainer.AddChild(keepGoingBtn)
+			}
+			
+			// スキップ枠が残っている場合のみスキップを許可
+			if session.CanSkipBreak() {
+				skipLabel := "Skip Break"
+				if remaining := session.SkipsRemaining(); remaining >= 0 {
+					skipLabel = fmt.Sprintf("Skip Break (%d left)", remaining)
 				}
-				a.audioService.PlayStartSound()
-				a.updateButtons()
-			})
-			a.buttonContainer.AddChild(skipBreakBtn)
+				skipBreakBtn := a.createButton(skipLabel, func() {
+					log.Printf("Skip Break clicked")
+					err := a.sessionService.SkipBreak()
+					if err != nil {
+						log.Printf("Failed to skip break: %v", err)
+						return
+					}
+					a.audioService.PlayStartSound()
+					a.updateButtons()
+				})
+				a.buttonContainer.AddChild(skipBreakBtn)
+			}
 		} else {
 			// 休憩セッション終了後または初期状態
 			startWorkBtn := a.createButton("Start Work", func() {
@@ -236,12 +323,42 @@ func (a *EbitenUIApp) updateButtons() {
 				a.updateButtons()
 			})
 			a.buttonContainer.AddChild(startWorkBtn)
+			
+			startFlowBtn := a.createButton("Start Flow", func() {
+				log.Printf("Start Flow clicked")
+				err := a.sessionService.StartFlowSession()
+				if err != nil {
+					log.Printf("Failed to start flow session: %v", err)
+					return
+				}
+				a.updateButtons()
+			})
+			a.buttonContainer.AddChild(startFlowBtn)
 		}
 	}
 	
 	log.Printf("Buttons updated, container has %d children", len(a.buttonContainer.Children()))
 }
 
+// addExtendButton adds a quick-extend button while the session may still be extended.
+func (a *EbitenUIApp) addExtendButton(session *domain.Session) {
+	if !session.CanExtend() {
+		return
+	}
+	
+	step := session.GetExtendPolicy().Step
+	extendBtn := a.createButton(fmt.Sprintf("+%d min", int(step.Minutes())), func() {
+		log.Printf("Extend clicked")
+		err := a.sessionService.ExtendSession(step)
+		if err != nil {
+			log.Printf("Failed to extend session: %v", err)
+			return
+		}
+		a.updateButtons()
+	})
+	a.buttonContainer.AddChild(extendBtn)
+}
+
 func (a *EbitenUIApp) updateProgressBar() {
 	session := a.sessionService.GetSession()
 	sessionState := session.GetState()
@@ -278,9 +395,18 @@ func (a *EbitenUIApp) updateProgressBar() {
 func (a *EbitenUIApp) Update() error {
 	a.ui.Update()
 	
-	// セッションサービスを更新
+	// 外部からの操作を実行してからセッションサービスを更新
+	if a.loop != nil {
+		a.loop.RunPending()
+	}
 	a.sessionService.Update()
 	
+	// テーマが変わったら新しい色で UI を作り直す
+	if a.themes.update(time.Now()) {
+		a.buildUI()
+		a.updateButtons()
+	}
+	
 	// プログレスバーを更新
 	a.updateProgressBar()
 	
@@ -288,8 +414,8 @@ func (a *EbitenUIApp) Update() error {
 }
 
 func (a *EbitenUIApp) Draw(screen *ebiten.Image) {
-	// 背景を暗い色で塗りつぶし
-	screen.Fill(color.RGBA{25, 25, 25, 255})
+	// 背景をテーマの色で塗りつぶし
+	screen.Fill(a.themes.current.Background)
 	
 	// ebitenuiを描画
 	a.ui.Draw(screen)
@@ -308,6 +434,18 @@ func (a *EbitenUIApp) drawOverlayText(screen *ebiten.Image) {
 	sessionState := session.GetState()
 	statusText := ""
 	switch sessionState {
+	case domain.Overtime:
+		elapsed := session.OvertimeElapsed()
+		timerText = fmt.Sprintf("+%02d:%02d", int(elapsed.Minutes()), int(elapsed.Seconds())%60)
+		statusText = "Overtime"
+	case domain.FlowSession:
+		elapsed := session.FlowElapsed()
+		timerText = fmt.Sprintf("%02d:%02d", int(elapsed.Minutes()), int(elapsed.Seconds())%60)
+		if session.IsSessionPaused() {
+			statusText = "Flow Session (Paused)"
+		} else {
+			statusText = fmt.Sprintf("Flow Session - break earned: %d min", int(session.EarnedFlowBreak().Minutes()))
+		}
 	case domain.WorkSession:
 		if session.IsSessionPaused() {
 			statusText = "Work Session (Paused)"
@@ -321,20 +459,18 @@ func (a *EbitenUIApp) drawOverlayText(screen *ebiten.Image) {
 			statusText = "Break Session"
 		}
 	default:
-		statusText = "Ready to start"
+		if session.IsAutoStartPending() {
+			statusText = fmt.Sprintf("%s starts in %ds", session.NextSessionType(), int(session.AutoStartRemaining().Seconds())+1)
+		} else {
+			statusText = "Ready to start"
+		}
 	}
 	
 	// タイマーテキストを描画（上部中央）
-	timerBounds := text.BoundString(basicfont.Face7x13, timerText)
-	timerX := screen.Bounds().Dx()/2 - timerBounds.Dx()/2
-	timerY := 60
-	text.Draw(screen, timerText, basicfont.Face7x13, timerX, timerY, color.White)
+	drawCenteredText(screen, timerText, a.fonts.Large, screen.Bounds().Dx()/2, 30, a.themes.current.Text)
 	
 	// ステータステキストを描画
-	statusBounds := text.BoundString(basicfont.Face7x13, statusText)
-	statusX := screen.Bounds().Dx()/2 - statusBounds.Dx()/2
-	statusY := 90
-	text.Draw(screen, statusText, basicfont.Face7x13, statusX, statusY, color.RGBA{200, 200, 200, 255})
+	drawCenteredText(screen, statusText, a.fonts.Body, screen.Bounds().Dx()/2, 75, a.themes.current.MutedText)
 	
 	// ボタンラベルを各ボタンの上に描画
 	buttonY := 220 // ボタンエリアの開始位置
@@ -343,9 +479,7 @@ func (a *EbitenUIApp) drawOverlayText(screen *ebiten.Image) {
 			continue
 		}
 		
-		labelBounds := text.BoundString(basicfont.Face7x13, label)
-		labelX := screen.Bounds().Dx()/2 - labelBounds.Dx()/2
-		text.Draw(screen, label, basicfont.Face7x13, labelX, buttonY, color.White)
+		drawCenteredText(screen, label, a.fonts.Button, screen.Bounds().Dx()/2, buttonY-lineHeight(a.fonts.Button), a.themes.current.ButtonText)
 		buttonY += 80 // 次のボタンの位置
 	}
 }
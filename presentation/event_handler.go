@@ -1,63 +1,50 @@
 package presentation
 
 import (
-	"time"
-
 	"github.com/hajimehoshi/ebiten/v2"
 
 	"karedoro/application"
 	"karedoro/domain"
 )
 
+// EventHandler adds the window behaviour on top of the sounds and
+// notifications played by application.FeedbackHandler.
 type EventHandler struct {
-	audioService        domain.AudioPlayer
-	notificationService domain.NotificationSender
+	audioService domain.AudioPlayer
+	feedback     *application.FeedbackHandler
 }
 
 func NewEventHandler(audioService domain.AudioPlayer, notificationService domain.NotificationSender) *EventHandler {
 	return &EventHandler{
-		audioService:        audioService,
-		notificationService: notificationService,
+		audioService: audioService,
+		feedback:     application.NewFeedbackHandler(audioService, notificationService),
 	}
 }
 
 func (eh *EventHandler) SetupCallbacks(sessionService *application.SessionService, onWorkSessionEnd, onBreakSessionEnd func()) {
-	sessionService.AddEventCallback(domain.EventWorkSessionStart, func() {
-		eh.audioService.PlayStartSound()
-		eh.notificationService.ShowWorkSessionStart()
-	})
+	eh.feedback.Attach(sessionService)
 	
-	sessionService.AddEventCallback(domain.EventBreakSessionStart, func() {
-		eh.audioService.PlayStartSound()
-		eh.notificationService.ShowBreakSessionStart()
+	sessionService.AddEventCallback(domain.EventWorkSessionEnd, func() {
+		ebiten.SetFullscreen(true)
+		onWorkSessionEnd()
 	})
 	
-	sessionService.AddEventCallback(domain.EventWorkSessionEnd, func() {
-		eh.audioService.PlayEndSound()
-		eh.notificationService.ShowWorkSessionEnd()
+	sessionService.AddEventCallback(domain.EventFlowSessionEnd, func() {
 		ebiten.SetFullscreen(true)
 		onWorkSessionEnd()
 	})
 	
 	sessionService.AddEventCallback(domain.EventBreakSessionEnd, func() {
-		eh.audioService.PlayEndSound()
-		eh.notificationService.ShowBreakSessionEnd()
 		ebiten.SetFullscreen(true)
 		onBreakSessionEnd()
 	})
 	
-	sessionService.AddEventCallback(domain.EventWarning, func() {
-		eh.audioService.PlayWarningSound()
-		eh.notificationService.ShowWarning()
-	})
-	
-	sessionService.AddEventCallback(domain.EventSessionPause, func() {
-		eh.audioService.PlayBeep(400, 100*time.Millisecond)
-		eh.notificationService.ShowSessionPaused()
+	// 緊急以上の警告では全画面を再表示する
+	sessionService.AddEventCallback(domain.EventWarningUrgent, func() {
+		ebiten.SetFullscreen(true)
 	})
 	
-	sessionService.AddEventCallback(domain.EventSessionResume, func() {
-		eh.audioService.PlayBeep(600, 100*time.Millisecond)
-		eh.notificationService.ShowSessionResumed()
+	sessionService.AddEventCallback(domain.EventWarningCritical, func() {
+		ebiten.SetFullscreen(true)
 	})
-}
\ No newline at end of file
+}
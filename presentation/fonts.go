@@ -0,0 +1,121 @@
+package presentation
+
+import (
+	"bytes"
+	_ "embed"
+	"image/color"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+
+	"karedoro/application"
+)
+
+// mplus1p is M+ 1p, which covers both Latin and Japanese text.
+//
+//go:embed fonts/mplus-1p-regular.ttf
+var mplus1p []byte
+
+var defaultFontSource = sync.OnceValue(func() *text.GoTextFaceSource {
+	source, err := text.NewGoTextFaceSource(bytes.NewReader(mplus1p))
+	if err != nil {
+		panic(err)
+	}
+	return source
+})
+
+// Fonts are the faces the window draws text with.
+type Fonts struct {
+	Body    text.Face
+	Large   text.Face
+	Message text.Face
+	Timer   text.Face
+	Button  text.Face
+	
+	// MiniTimer is the countdown of the mini timer window.
+	MiniTimer text.Face
+}
+
+// LoadFonts reads a TrueType or OpenType font from path; an empty path uses
+// the built-in font.
+func LoadFonts(path string) (*Fonts, error) {
+	if path == "" {
+		return DefaultFonts(), nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	source, err := text.NewGoTextFaceSource(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	return newFonts(source), nil
+}
+
+// DefaultFonts returns the faces of the built-in font.
+func DefaultFonts() *Fonts {
+	return newFonts(defaultFontSource())
+}
+
+// loadConfiguredFonts loads the font set in the config, relative to the
+// config directory, keeping the built-in font if it cannot be read.
+func loadConfiguredFonts(configService *application.ConfigService) *Fonts {
+	path := configService.GetConfig().Font
+	if path != "" && !filepath.IsAbs(path) {
+		path = filepath.Join(configService.Dir(), path)
+	}
+	fonts, err := LoadFonts(path)
+	if err != nil {
+		log.Printf("Warning: using the built-in font: %v", err)
+		return DefaultFonts()
+	}
+	return fonts
+}
+
+func newFonts(source *text.GoTextFaceSource) *Fonts {
+	face := func(size float64) text.Face {
+		return &text.GoTextFace{Source: source, Size: size}
+	}
+	return &Fonts{
+		Body:    face(FontSize),
+		Large:   face(LargeFontSize),
+		Message: face(MessageFontSize),
+		Timer:   face(TimerFontSize),
+		Button:  face(ButtonFontSize),
+		
+		MiniTimer: face(MiniTimerFontSize),
+	}
+}
+
+// lineHeight is the height of one line of face.
+func lineHeight(face text.Face) int {
+	metrics := face.Metrics()
+	return int(metrics.HAscent + metrics.HDescent)
+}
+
+// drawCenteredText draws s centred horizontally on x with its top at y.
+func drawCenteredText(screen *ebiten.Image, s string, face text.Face, x, y int, c color.Color) {
+	op := &text.DrawOptions{}
+	op.GeoM.Translate(float64(x), float64(y))
+	op.ColorScale.ScaleWithColor(c)
+	op.PrimaryAlign = text.AlignCenter
+	text.Draw(screen, s, face, op)
+}
+
+// textWidth measures s in face.
+func textWidth(s string, face text.Face) int {
+	return int(text.Advance(s, face) + 0.5)
+}
+
+// drawTextAt draws s with its top left corner at x, y.
+func drawTextAt(screen *ebiten.Image, s string, face text.Face, x, y int, c color.Color) {
+	op := &text.DrawOptions{}
+	op.GeoM.Translate(float64(x), float64(y))
+	op.ColorScale.ScaleWithColor(c)
+	text.Draw(screen, s, face, op)
+}
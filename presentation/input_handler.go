@@ -1,20 +1,21 @@
 package presentation
 
 import (
-	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/inpututil"
 	"karedoro/application"
 	"karedoro/domain"
 )
 
-// InputHandler manages user input processing.
+// InputHandler manages user input processing. Actions that have a button,
+// such as starting a session, are handled by the ButtonManager.
 type InputHandler struct {
 	sessionService *application.SessionService
+	keymap         *Keymap
 }
 
-func NewInputHandler(sessionService *application.SessionService) *InputHandler {
+func NewInputHandler(sessionService *application.SessionService, keymap *Keymap) *InputHandler {
 	return &InputHandler{
 		sessionService: sessionService,
+		keymap:         keymap,
 	}
 }
 
@@ -22,13 +23,34 @@ func (ih *InputHandler) HandleInput() {
 	session := ih.sessionService.GetSession()
 	
 	switch session.GetState() {
-	case domain.WorkSession, domain.BreakSession:
-		if inpututil.IsKeyJustPressed(ebiten.KeySpace) {
+	case domain.WorkSession, domain.BreakSession, domain.FlowSession:
+		if session.GetState() == domain.FlowSession && ih.keymap.Pressed(application.ActionStopFlow) {
+			ih.sessionService.StopFlowSession()
+			return
+		}
+		if ih.keymap.Pressed(application.ActionAbandon) {
+			ih.sessionService.AbandonSession()
+			return
+		}
+		if ih.keymap.Pressed(application.ActionPause) {
 			if session.IsSessionPaused() {
 				ih.sessionService.ResumeSession()
-			} else {
+			} else if session.CanPause() {
 				ih.sessionService.PauseSession()
 			}
 		}
+		if ih.keymap.Pressed(application.ActionExtend) && session.CanExtend() {
+			ih.sessionService.ExtendSession(session.GetExtendPolicy().Step)
+		}
+	case domain.Idle:
+		if ih.keymap.Pressed(application.ActionCancelAutoStart) {
+			ih.sessionService.CancelAutoStart()
+		}
+	case domain.Overtime:
+		if ih.keymap.Pressed(application.ActionStartBreak) {
+			ih.sessionService.StartBreakSession()
+		} else if ih.keymap.Pressed(application.ActionStopOvertime) {
+			ih.sessionService.StopOvertime()
+		}
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,83 @@
+package presentation
+
+import (
+	"log"
+	"unicode/utf8"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"karedoro/application"
+)
+
+// Keymap tells which of the configured actions were pressed this frame.
+type Keymap struct {
+	config application.Keymap
+	keys   map[string][]ebiten.Key
+	
+	// chars are single characters such as "+", matched as typed so that
+	// they work whichever key produces them.
+	chars map[string][]rune
+}
+
+// NewKeymap resolves the key names in config. Unknown names are logged and
+// ignored.
+func NewKeymap(config application.Keymap) *Keymap {
+	km := &Keymap{
+		config: config,
+		keys:   make(map[string][]ebiten.Key),
+		chars:  make(map[string][]rune),
+	}
+	for action, names := range config {
+		for _, name := range names {
+			var key ebiten.Key
+			if err := key.UnmarshalText([]byte(name)); err == nil {
+				km.keys[action] = append(km.keys[action], key)
+				continue
+			}
+			if r, size := utf8.DecodeRuneInString(name); size == len(name) && r != utf8.RuneError {
+				km.chars[action] = append(km.chars[action], r)
+				continue
+			}
+			log.Printf("Warning: unknown key %q for %s is ignored", name, action)
+		}
+	}
+	return km
+}
+
+// DefaultKeymap uses the default keys.
+func DefaultKeymap() *Keymap {
+	return NewKeymap(application.DefaultKeymap())
+}
+
+// Pressed reports whether a key of action was pressed this frame.
+func (km *Keymap) Pressed(action string) bool {
+	for _, key := range km.keys[action] {
+		if inpututil.IsKeyJustPressed(key) {
+			return true
+		}
+	}
+	if chars := km.chars[action]; len(chars) > 0 {
+		for _, r := range ebiten.AppendInputChars(nil) {
+			for _, c := range chars {
+				if r == c {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// Label is how the key of action is shown, such as "SPACE".
+func (km *Keymap) Label(action string) string {
+	return km.config.Label(action)
+}
+
+// Labels returns the label of every action, for the message templates.
+func (km *Keymap) Labels() map[string]string {
+	labels := make(map[string]string, len(km.config))
+	for action := range km.config {
+		labels[action] = km.config.Label(action)
+	}
+	return labels
+}
@@ -0,0 +1,101 @@
+package presentation
+
+import (
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// miniMode shrinks the window to a small undecorated window that stays on
+// top with just the countdown, and restores it. Without a title bar the
+// mini window is moved by dragging it, and a double click restores it.
+type miniMode struct {
+	active bool
+	
+	// The full window, restored when leaving mini mode.
+	x, y, width, height int
+	
+	// Where the mini window was last, so that it comes back there.
+	miniX, miniY int
+	placed       bool
+	
+	dragging     bool
+	dragX, dragY int
+	lastClick    time.Time
+}
+
+func newMiniMode() *miniMode {
+	return &miniMode{}
+}
+
+func (m *miniMode) toggle() {
+	if m.active {
+		m.exit()
+	} else {
+		m.enter()
+	}
+}
+
+func (m *miniMode) enter() {
+	if m.active {
+		return
+	}
+	m.active = true
+	m.x, m.y = ebiten.WindowPosition()
+	m.width, m.height = ebiten.WindowSize()
+	
+	// 初回はウィンドウの右上に置く
+	if !m.placed {
+		m.miniX, m.miniY = m.x+m.width-MiniWindowSize, m.y
+		m.placed = true
+	}
+	
+	ebiten.SetWindowResizingMode(ebiten.WindowResizingModeDisabled)
+	ebiten.SetWindowDecorated(false)
+	ebiten.SetWindowFloating(true)
+	ebiten.SetWindowSize(MiniWindowSize, MiniWindowSize)
+	ebiten.SetWindowPosition(m.miniX, m.miniY)
+}
+
+func (m *miniMode) exit() {
+	if !m.active {
+		return
+	}
+	m.active = false
+	m.dragging = false
+	m.miniX, m.miniY = ebiten.WindowPosition()
+	
+	ebiten.SetWindowFloating(false)
+	ebiten.SetWindowDecorated(true)
+	ebiten.SetWindowSize(m.width, m.height)
+	ebiten.SetWindowPosition(m.x, m.y)
+	ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
+}
+
+// update moves the mini window while it is dragged and restores the full
+// window on a double click.
+func (m *miniMode) update() {
+	x, y := ebiten.CursorPosition()
+	
+	switch {
+	case inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft):
+		now := time.Now()
+		if now.Sub(m.lastClick) < MiniDoubleClickInterval {
+			m.lastClick = time.Time{}
+			m.exit()
+			return
+		}
+		m.lastClick = now
+		m.dragging = true
+		m.dragX, m.dragY = x, y
+	case m.dragging && ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft):
+		// カーソルがつかんだ位置に戻るようウィンドウを動かす
+		if x != m.dragX || y != m.dragY {
+			wx, wy := ebiten.WindowPosition()
+			ebiten.SetWindowPosition(wx+x-m.dragX, wy+y-m.dragY)
+		}
+	default:
+		m.dragging = false
+	}
+}
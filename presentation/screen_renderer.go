@@ -3,72 +3,229 @@ package presentation
 import (
 	"fmt"
 	"image/color"
+	"math"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
 
+	"karedoro/application"
 	"karedoro/domain"
+	"karedoro/i18n"
+	"karedoro/theme"
 )
 
-type ScreenRenderer struct{}
+type ScreenRenderer struct {
+	flashing bool
+	messages *i18n.Catalog
+	fonts    *Fonts
+	theme    *theme.Theme
+	
+	// keys are the labels of the keys in the hints, by action.
+	keys map[string]string
+	
+	// statsHidden hides today's counts on the idle screen.
+	statsHidden bool
+	
+	// pairingCode returns the web UI pairing code; nil when the web UI is off.
+	pairingCode func() string
+}
 
 func NewScreenRenderer() *ScreenRenderer {
-	return &ScreenRenderer{}
+	return &ScreenRenderer{messages: i18n.Default(), fonts: DefaultFonts(), theme: theme.Default(), keys: DefaultKeymap().Labels()}
+}
+
+// SetMessages renders the screen texts from messages.
+func (sr *ScreenRenderer) SetMessages(messages *i18n.Catalog) {
+	sr.messages = messages
+}
+
+// SetFonts draws the screen texts with fonts.
+func (sr *ScreenRenderer) SetFonts(fonts *Fonts) {
+	sr.fonts = fonts
+}
+
+// SetTheme draws the screens in the colours of t.
+func (sr *ScreenRenderer) SetTheme(t *theme.Theme) {
+	sr.theme = t
+}
+
+// SetKeymap names the keys of keymap in the hints.
+func (sr *ScreenRenderer) SetKeymap(keymap *Keymap) {
+	sr.keys = keymap.Labels()
 }
 
-func (sr *ScreenRenderer) DrawMainScreen(screen *ebiten.Image, session *domain.Session, buttonManager *ButtonManager) {
+func (sr *ScreenRenderer) text(key string, data i18n.Data) string {
+	data.Keys = sr.keys
+	return sr.messages.Text(key, data)
+}
+
+// keyHint renders a hint about the keys of actions; empty when one of them
+// has no key.
+func (sr *ScreenRenderer) keyHint(key string, data i18n.Data, actions ...string) string {
+	for _, action := range actions {
+		if sr.keys[action] == "" {
+			return ""
+		}
+	}
+	return sr.text(key, data)
+}
+
+// ToggleStats shows or hides today's counts on the idle screen.
+func (sr *ScreenRenderer) ToggleStats() {
+	sr.statsHidden = !sr.statsHidden
+}
+
+func (sr *ScreenRenderer) SetFlashing(flashing bool) {
+	sr.flashing = flashing
+}
+
+// SetPairingCode shows the web UI pairing code on the idle screen.
+func (sr *ScreenRenderer) SetPairingCode(code func() string) {
+	sr.pairingCode = code
+}
+
+func (sr *ScreenRenderer) DrawMainScreen(screen *ebiten.Image, session *domain.Session, today application.DailyStats, buttonManager *ButtonManager) {
 	switch session.GetState() {
 	case domain.WorkSession:
 		sr.drawWorkSession(screen, session)
 	case domain.BreakSession:
 		sr.drawBreakSession(screen, session)
+	case domain.Overtime:
+		sr.drawOvertime(screen, session)
+	case domain.FlowSession:
+		sr.drawFlowSession(screen, session)
 	case domain.Idle:
-		sr.drawIdleScreen(screen, buttonManager)
+		sr.drawIdleScreen(screen, today, buttonManager)
 	}
 }
 
 func (sr *ScreenRenderer) DrawFullscreenOverlay(screen *ebiten.Image, session *domain.Session, buttonManager *ButtonManager) {
-	screenWidth, screenHeight := ebiten.WindowSize()
+	screenWidth, _ := ebiten.WindowSize()
 	
-	// 強制的な赤い背景で注意を引く
-	screen.Fill(ForceRedBackground)
+	// 強制的な赤い背景で注意を引く（最終警告では点滅させる）
+	screen.Fill(sr.overlayBackground())
 	
 	var message string
-	if session.GetSessionType() == domain.Work {
-		message = WorkSessionEndMessage
-	} else {
-		message = BreakSessionEndMessage
+	switch session.GetSessionType() {
+	case domain.Work:
+		message = sr.text("overlay.work_end", i18n.Data{})
+	case domain.Flow:
+		message = sr.text("overlay.flow_end", i18n.Data{})
+	default:
+		message = sr.text("overlay.break_end", i18n.Data{})
+	}
+	
+	step := lineHeight(sr.fonts.Body) + LineSpacing
+	top, bottom := buttonManager.Bounds()
+	
+	// ボタンの上へ下から順に積む
+	y := top - OverlayMargin - step
+	if session.IsBreakDue() {
+		if session.NextBreakDuration() != session.GetDuration(domain.Break) || session.GetSessionType() == domain.Flow {
+			breakMsg := sr.text("overlay.next_break", i18n.Data{Duration: i18n.Duration(session.NextBreakDuration())})
+			sr.drawText(screen, breakMsg, screenWidth, y)
+			y -= step
+		}
 	}
 	
-	// メッセージを大きく強調表示
-	messageX := screenWidth/2 - len(message)*TextCharWidthLg
-	messageY := screenHeight/3
+	// 警告メッセージを追加
+	warningMsg := sr.text("overlay.cannot_continue", i18n.Data{})
+	sr.drawText(screen, warningMsg, screenWidth, y)
 	
-	// 背景の強調ボックス
-	boxWidth := len(message)*8 + MessageBoxPadding
+	// メッセージを大きく強調表示（背景の強調ボックスは文字幅に合わせる）
+	boxWidth := textWidth(message, sr.fonts.Message) + MessageBoxPadding
 	boxHeight := MessageBoxHeight
 	boxX := screenWidth/2 - boxWidth/2
-	boxY := messageY - 20
-	drawRect(screen, boxX, boxY, boxWidth, boxHeight, ForceYellowBox)
-	drawBorder(screen, boxX, boxY, boxWidth, boxHeight, WhiteBorder, MessageBoxBorderWidth)
+	boxY := y - OverlayMargin - boxHeight
+	drawRect(screen, boxX, boxY, boxWidth, boxHeight, sr.theme.OverlayBox)
+	drawBorder(screen, boxX, boxY, boxWidth, boxHeight, sr.theme.OverlayBoxBorder, MessageBoxBorderWidth)
+	drawCenteredText(screen, message, sr.fonts.Message, screenWidth/2, boxY+(boxHeight-lineHeight(sr.fonts.Message))/2, sr.theme.OverlayBoxText)
 	
-	ebitenutil.DebugPrintAt(screen, message, messageX, messageY)
+	// ボタンの下
+	y = bottom + OverlayMargin
+	if session.IsBreakDue() {
+		if skipMsg := sr.skipAllowanceText(session); skipMsg != "" {
+			sr.drawText(screen, skipMsg, screenWidth, y)
+			y += step
+		}
+	}
 	
-	// 警告メッセージを追加
-	warningMsg := "YOU CANNOT CONTINUE UNTIL YOU CHOOSE!"
-	warningX := screenWidth/2 - len(warningMsg)*TextCharWidth
-	warningY := screenHeight/2 - TextLineHeight
-	ebitenutil.DebugPrintAt(screen, warningMsg, warningX, warningY)
+	if session.IsAutoStartPending() {
+		autoMsg := sr.text("overlay.auto_start", i18n.Data{
+			Session: sr.messages.SessionType(session.NextSessionType()),
+			Count:   int(session.AutoStartRemaining().Seconds()) + 1,
+		})
+		sr.drawText(screen, autoMsg, screenWidth, y)
+	}
 	
 	buttonManager.DrawButtons(screen)
 }
 
+func (sr *ScreenRenderer) skipAllowanceText(session *domain.Session) string {
+	remaining := session.SkipsRemaining()
+	switch {
+	case remaining > 0:
+		return sr.text("skip.left", i18n.Data{Count: remaining})
+	case remaining < 0:
+		return ""
+	case session.GetSkipPolicy().Cooldown > 0:
+		return sr.text("skip.cooldown", i18n.Data{Duration: i18n.Duration(session.SkipCooldownRemaining())})
+	default:
+		return sr.text("skip.none", i18n.Data{})
+	}
+}
+
 func (sr *ScreenRenderer) drawWorkSession(screen *ebiten.Image, session *domain.Session) {
-	sr.drawSessionState(screen, session, WorkSessionColor, WorkingText)
+	sr.drawSessionState(screen, session, sr.theme.Work, sr.text("screen.working", i18n.Data{}))
 }
 
 func (sr *ScreenRenderer) drawBreakSession(screen *ebiten.Image, session *domain.Session) {
-	sr.drawSessionState(screen, session, BreakSessionColor, BreakText)
+	sr.drawSessionState(screen, session, sr.theme.Break, sr.text("screen.break", i18n.Data{}))
+}
+
+// drawOvertime counts up the overtime worked and down to the mandatory break.
+func (sr *ScreenRenderer) drawOvertime(screen *ebiten.Image, session *domain.Session) {
+	elapsed := session.OvertimeElapsed()
+	remaining := session.OvertimeRemaining()
+	screenWidth, screenHeight := ebiten.WindowSize()
+	
+	screen.Fill(sr.theme.Overtime)
+	
+	timerText := fmt.Sprintf("+%02d:%02d", int(elapsed.Minutes()), int(elapsed.Seconds())%60)
+	sr.drawTimer(screen, timerText, screenWidth, screenHeight)
+	
+	sr.drawProgressBar(screen, session.GetOvertimeProgress(), screenWidth, screenHeight)
+	
+	overtimeText := sr.text("screen.overtime", i18n.Data{})
+	capText := sr.text("screen.overtime_cap", i18n.Data{Duration: i18n.Duration(remaining)})
+	keysText := sr.keyHint("gui.overtime_keys", i18n.Data{}, application.ActionStartBreak, application.ActionStopOvertime)
+	sr.drawText(screen, overtimeText, screenWidth, screenHeight/2-TextLineHeight)
+	sr.drawText(screen, capText, screenWidth, screenHeight/2-20)
+	sr.drawText(screen, keysText, screenWidth, screenHeight/2+ProgressBarOffsetY+TextLineHeight)
+}
+
+// drawFlowSession counts up the flow session with the break it has earned so far.
+func (sr *ScreenRenderer) drawFlowSession(screen *ebiten.Image, session *domain.Session) {
+	elapsed := session.FlowElapsed()
+	screenWidth, screenHeight := ebiten.WindowSize()
+	
+	screen.Fill(sr.theme.Flow)
+	
+	timerText := fmt.Sprintf("%02d:%02d", int(elapsed.Minutes()), int(elapsed.Seconds())%60)
+	sr.drawTimer(screen, timerText, screenWidth, screenHeight)
+	
+	if session.IsSessionPaused() {
+		sr.drawPaused(screen, screenWidth, screenHeight)
+	} else {
+		flowText := sr.text("screen.flow", i18n.Data{})
+		instruction := sr.keyHint("gui.flow_stop", i18n.Data{}, application.ActionStopFlow)
+		sr.drawText(screen, flowText, screenWidth, screenHeight/2-TextLineHeight)
+		sr.drawText(screen, instruction, screenWidth, screenHeight/2-20)
+	}
+	
+	earned := sr.text("screen.flow_break", i18n.Data{Duration: i18n.Duration(session.EarnedFlowBreak())})
+	sr.drawText(screen, earned, screenWidth, screenHeight/2+ProgressBarOffsetY)
 }
 
 func (sr *ScreenRenderer) drawSessionState(screen *ebiten.Image, session *domain.Session, sessionColor color.Color, statusText string) {
@@ -78,29 +235,172 @@ func (sr *ScreenRenderer) drawSessionState(screen *ebiten.Image, session *domain
 	screen.Fill(sessionColor)
 	
 	timerText := fmt.Sprintf("%02d:%02d", int(remaining.Minutes()), int(remaining.Seconds())%60)
-	// Draw timer background for better visibility
-	timerX := screenWidth/2 - TimerOffsetX
-	timerY := screenHeight/2 - TimerOffsetY
-	drawRect(screen, timerX, timerY, TimerBoxWidth, TimerBoxHeight, BlackShadow)
-	ebitenutil.DebugPrintAt(screen, timerText, screenWidth/2-TimerOffsetX+10, screenHeight/2-TimerOffsetY+10)
+	sr.drawTimer(screen, timerText, screenWidth, screenHeight)
 	
 	// Draw progress bar
 	sr.drawProgressBar(screen, session.GetProgress(), screenWidth, screenHeight)
 	
 	if session.IsSessionPaused() {
-		ebitenutil.DebugPrintAt(screen, PausedText, screenWidth/2-len(PausedText)*TextCharWidth, screenHeight/2-TextLineHeight)
-		ebitenutil.DebugPrintAt(screen, ResumeInstructionText, screenWidth/2-len(ResumeInstructionText)*TextCharWidth, screenHeight/2-20)
+		sr.drawPaused(screen, screenWidth, screenHeight)
 	} else {
-		ebitenutil.DebugPrintAt(screen, statusText, screenWidth/2-len(statusText)*TextCharWidth, screenHeight/2-TextLineHeight)
-		ebitenutil.DebugPrintAt(screen, PauseInstructionText, screenWidth/2-len(PauseInstructionText)*TextCharWidth, screenHeight/2-20)
+		sr.drawText(screen, statusText, screenWidth, screenHeight/2-TextLineHeight)
+		instruction := sr.pauseInstruction(session)
+		sr.drawText(screen, instruction, screenWidth, screenHeight/2-20)
+	}
+	
+	budget := sr.pauseBudgetText(session)
+	if budget != "" {
+		sr.drawText(screen, budget, screenWidth, screenHeight/2+ProgressBarOffsetY+TextLineHeight)
+	}
+	
+	extend := sr.extendText(session)
+	if extend != "" {
+		sr.drawText(screen, extend, screenWidth, screenHeight/2+ProgressBarOffsetY+2*TextLineHeight)
+	}
+}
+
+func (sr *ScreenRenderer) drawPaused(screen *ebiten.Image, screenWidth, screenHeight int) {
+	pausedText := sr.text("screen.paused", i18n.Data{})
+	instruction := sr.keyHint("gui.resume", i18n.Data{}, application.ActionPause)
+	sr.drawText(screen, pausedText, screenWidth, screenHeight/2-TextLineHeight)
+	sr.drawText(screen, instruction, screenWidth, screenHeight/2-20)
+}
+
+func (sr *ScreenRenderer) extendText(session *domain.Session) string {
+	text := ""
+	if session.Extensions() > 0 {
+		text = sr.text("screen.extended", i18n.Data{Duration: i18n.Duration(session.ExtendedBy())})
+	}
+	if !session.CanExtend() {
+		return text
+	}
+	
+	hint := sr.keyHint("gui.extend", i18n.Data{
+		Duration: i18n.Duration(session.GetExtendPolicy().Step),
+		Count:    session.ExtensionsRemaining(),
+	}, application.ActionExtend)
+	if text != "" && hint != "" {
+		text += "  "
+	}
+	return text + hint
+}
+
+func (sr *ScreenRenderer) pauseInstruction(session *domain.Session) string {
+	switch {
+	case session.GetPausePolicy().Strict:
+		return sr.text("screen.strict", i18n.Data{})
+	case !session.CanPause():
+		return sr.text("screen.no_pauses", i18n.Data{})
+	default:
+		return sr.keyHint("gui.pause", i18n.Data{}, application.ActionPause)
+	}
+}
+
+func (sr *ScreenRenderer) pauseBudgetText(session *domain.Session) string {
+	policy := session.GetPausePolicy()
+	if policy.Strict {
+		return ""
+	}
+	
+	text := ""
+	if policy.MaxPauses > 0 {
+		text = sr.text("screen.pauses", i18n.Data{Count: session.PausesUsed(), Limit: policy.MaxPauses})
 	}
+	if policy.MaxPauseTime > 0 {
+		left := policy.MaxPauseTime - session.PauseTimeUsed()
+		if left < 0 {
+			left = 0
+		}
+		if text != "" {
+			text += "  "
+		}
+		text += sr.text("screen.pause_time_left", i18n.Data{Duration: i18n.Duration(left)})
+	}
+	return text
 }
 
-func (sr *ScreenRenderer) drawIdleScreen(screen *ebiten.Image, buttonManager *ButtonManager) {
+func (sr *ScreenRenderer) drawIdleScreen(screen *ebiten.Image, today application.DailyStats, buttonManager *ButtonManager) {
 	screenWidth, screenHeight := ebiten.WindowSize()
-	ebitenutil.DebugPrintAt(screen, IdleScreenMessage, screenWidth/2-len(IdleScreenMessage)*TextCharWidth, screenHeight/2-IdleMessageOffset)
+	idleText := sr.text("screen.idle", i18n.Data{})
+	drawCenteredText(screen, idleText, sr.fonts.Large, screenWidth/2, screenHeight/2-IdleMessageOffset, sr.theme.Text)
+	
+	if !sr.statsHidden {
+		statsText := sr.text("screen.today_stats", i18n.Data{CompletedToday: today.WorkSessionsCompleted, SkippedToday: today.BreaksSkipped})
+		sr.drawText(screen, statsText, screenWidth, screenHeight/2+IdleMessageOffset)
+	}
 	
 	buttonManager.DrawButtons(screen)
+	
+	if sr.pairingCode != nil {
+		pairing := sr.text("screen.pairing", i18n.Data{Code: sr.pairingCode()})
+		sr.drawText(screen, pairing, screenWidth, screenHeight-2*TextLineHeight)
+	}
+}
+
+// DrawMiniTimer draws the mini timer window: the countdown in the colour of
+// the session, inside a thin ring showing its progress.
+func (sr *ScreenRenderer) DrawMiniTimer(screen *ebiten.Image, session *domain.Session) {
+	width, height := screen.Bounds().Dx(), screen.Bounds().Dy()
+	
+	background := sr.theme.Background
+	timerText := "--:--"
+	progress := 0.0
+	switch session.GetState() {
+	case domain.WorkSession, domain.BreakSession:
+		background = sr.theme.Work
+		if session.GetState() == domain.BreakSession {
+			background = sr.theme.Break
+		}
+		remaining := session.GetTimeRemaining()
+		timerText = fmt.Sprintf("%02d:%02d", int(remaining.Minutes()), int(remaining.Seconds())%60)
+		progress = session.GetProgress()
+	case domain.Overtime:
+		background = sr.theme.Overtime
+		elapsed := session.OvertimeElapsed()
+		timerText = fmt.Sprintf("+%02d:%02d", int(elapsed.Minutes()), int(elapsed.Seconds())%60)
+		progress = session.GetOvertimeProgress()
+	case domain.FlowSession:
+		// フローには終わりがないので輪は空のまま
+		background = sr.theme.Flow
+		elapsed := session.FlowElapsed()
+		timerText = fmt.Sprintf("%02d:%02d", int(elapsed.Minutes()), int(elapsed.Seconds())%60)
+	}
+	screen.Fill(background)
+	
+	cx, cy := float32(width)/2, float32(height)/2
+	radius := float32(min(width, height))/2 - MiniRingMargin
+	vector.StrokeCircle(screen, cx, cy, radius, MiniRingWidth, sr.theme.ProgressBackground, true)
+	
+	// 12時の位置から時計回りに進捗の分だけ描く
+	segments := int(float64(MiniRingSegments) * progress)
+	for i := 0; i < segments; i++ {
+		a0 := 2*math.Pi*float64(i)/MiniRingSegments - math.Pi/2
+		a1 := 2*math.Pi*float64(i+1)/MiniRingSegments - math.Pi/2
+		vector.StrokeLine(screen,
+			cx+radius*float32(math.Cos(a0)), cy+radius*float32(math.Sin(a0)),
+			cx+radius*float32(math.Cos(a1)), cy+radius*float32(math.Sin(a1)),
+			MiniRingWidth, sr.theme.Progress, true)
+	}
+	
+	// 一時停止中は数字を薄くする
+	textColor := color.Color(sr.theme.Text)
+	if session.IsSessionPaused() {
+		textColor = sr.theme.MutedText
+	}
+	drawCenteredText(screen, timerText, sr.fonts.MiniTimer, width/2, height/2-lineHeight(sr.fonts.MiniTimer)/2, textColor)
+}
+
+// drawText draws s in the body font, centred on the screen.
+func (sr *ScreenRenderer) drawText(screen *ebiten.Image, s string, screenWidth, y int) {
+	drawCenteredText(screen, s, sr.fonts.Body, screenWidth/2, y, sr.theme.Text)
+}
+
+// drawTimer draws the countdown in large digits above the status text, with
+// a drop shadow to keep it readable on any session colour.
+func (sr *ScreenRenderer) drawTimer(screen *ebiten.Image, timerText string, screenWidth, screenHeight int) {
+	y := screenHeight/2 - TimerOffsetY - lineHeight(sr.fonts.Timer)/2
+	drawCenteredText(screen, timerText, sr.fonts.Timer, screenWidth/2+TimerShadowOffset, y+TimerShadowOffset, sr.theme.Shadow)
+	drawCenteredText(screen, timerText, sr.fonts.Timer, screenWidth/2, y, sr.theme.Text)
 }
 
 func (sr *ScreenRenderer) drawProgressBar(screen *ebiten.Image, progress float64, screenWidth, screenHeight int) {
@@ -108,14 +408,21 @@ func (sr *ScreenRenderer) drawProgressBar(screen *ebiten.Image, progress float64
 	barY := screenHeight/2 + ProgressBarOffsetY
 	
 	// Draw progress bar background
-	drawRect(screen, barX, barY, ProgressBarWidth, ProgressBarHeight, ProgressBarBg)
+	drawRect(screen, barX, barY, ProgressBarWidth, ProgressBarHeight, sr.theme.ProgressBackground)
 	
 	// Draw progress bar fill
 	progressWidth := int(float64(ProgressBarWidth) * progress)
 	if progressWidth > 0 {
-		drawRect(screen, barX, barY, progressWidth, ProgressBarHeight, ProgressBarFill)
+		drawRect(screen, barX, barY, progressWidth, ProgressBarHeight, sr.theme.Progress)
 	}
 	
 	// Draw progress bar border
-	drawBorder(screen, barX, barY, ProgressBarWidth, ProgressBarHeight, ProgressBarBorder, 1)
-}
\ No newline at end of file
+	drawBorder(screen, barX, barY, ProgressBarWidth, ProgressBarHeight, sr.theme.ProgressBorder, 1)
+}
+
+func (sr *ScreenRenderer) overlayBackground() color.Color {
+	if sr.flashing && time.Now().UnixMilli()/OverlayFlashInterval.Milliseconds()%2 == 1 {
+		return sr.theme.OverlayFlash
+	}
+	return sr.theme.Overlay
+}
@@ -0,0 +1,433 @@
+package presentation
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+
+	"karedoro/application"
+	"karedoro/domain"
+	"karedoro/i18n"
+	"karedoro/theme"
+)
+
+// settingKind is how a setting is shown and changed.
+type settingKind int
+
+const (
+	settingMinutes settingKind = iota
+	settingPercent
+	settingSwitch
+)
+
+// The settings in the order they are listed.
+const (
+	settingWork = iota
+	settingBreak
+	settingWarning
+	settingVolume
+	settingSound
+	settingNotifications
+)
+
+// setting is one row of the settings screen. Numbers can be stepped or
+// typed; typed digits are kept in input until the settings are saved.
+type setting struct {
+	label    string
+	kind     settingKind
+	min, max int
+	step     int
+	value    int
+	input    string
+}
+
+// settingControl is a stepper or switch button on a setting's row.
+type settingControl struct {
+	Button
+	row int
+}
+
+// SettingsEditor is the settings screen opened from the idle screen. It
+// edits a copy of the config, which is only applied when it is saved.
+type SettingsEditor struct {
+	configService *application.ConfigService
+	audio         domain.AudioPlayer
+	
+	// save applies and saves the edited config; onClose leaves the screen.
+	save    func(*application.Config) error
+	onClose func()
+	
+	settings []setting
+	controls []settingControl
+	buttons  []Button
+	focus    int
+	errText  string
+	
+	messages *i18n.Catalog
+	fonts    *Fonts
+	theme    *theme.Theme
+}
+
+func NewSettingsEditor(configService *application.ConfigService, audio domain.AudioPlayer) *SettingsEditor {
+	se := &SettingsEditor{
+		configService: configService,
+		audio:         audio,
+		messages:      i18n.Default(),
+		fonts:         DefaultFonts(),
+		theme:         theme.Default(),
+	}
+	se.buildButtons()
+	return se
+}
+
+// SetMessages labels the settings from messages.
+func (se *SettingsEditor) SetMessages(messages *i18n.Catalog) {
+	se.messages = messages
+	se.buildButtons()
+}
+
+// SetFonts draws the settings with fonts.
+func (se *SettingsEditor) SetFonts(fonts *Fonts) {
+	se.fonts = fonts
+}
+
+// SetTheme draws the settings in the colours of t.
+func (se *SettingsEditor) SetTheme(t *theme.Theme) {
+	se.theme = t
+}
+
+func (se *SettingsEditor) text(key string, data i18n.Data) string {
+	return se.messages.Text(key, data)
+}
+
+// buildButtons creates the preview, save and cancel buttons; their positions
+// are set by layout.
+func (se *SettingsEditor) buildButtons() {
+	preview := func(play func(domain.AudioPlayer) error) func() {
+		return func() { se.preview(play) }
+	}
+	canPreview := func() bool {
+		_, ok := se.audio.(*application.AudioService)
+		return ok
+	}
+	
+	se.buttons = []Button{
+		{Text: se.text("button.preview_start", i18n.Data{}), Action: preview(domain.AudioPlayer.PlayStartSound), Available: canPreview},
+		{Text: se.text("button.preview_end", i18n.Data{}), Action: preview(domain.AudioPlayer.PlayEndSound), Available: canPreview},
+		{Text: se.text("button.preview_warning", i18n.Data{}), Action: preview(domain.AudioPlayer.PlayWarningSound), Available: canPreview},
+		{Text: se.text("button.save", i18n.Data{}), Action: se.submit},
+		{Text: se.text("button.cancel", i18n.Data{}), Action: se.close},
+	}
+}
+
+// Open starts editing the current config.
+func (se *SettingsEditor) Open() {
+	config := se.configService.GetConfig()
+	se.settings = []setting{
+		settingWork:          {label: "settings.work", kind: settingMinutes, min: 1, max: 180, step: 5, value: wholeMinutes(config.WorkDuration)},
+		settingBreak:         {label: "settings.break", kind: settingMinutes, min: 1, max: 60, step: 1, value: wholeMinutes(config.BreakDuration)},
+		settingWarning:       {label: "settings.warning", kind: settingMinutes, min: 1, max: 60, step: 1, value: wholeMinutes(config.WarningInterval)},
+		settingVolume:        {label: "settings.volume", kind: settingPercent, min: 0, max: 100, step: 10, value: int(math.Round(config.Volume * 100))},
+		settingSound:         {label: "settings.sound", kind: settingSwitch, max: 1, value: switchValue(config.SoundEnabled)},
+		settingNotifications: {label: "settings.notifications", kind: settingSwitch, max: 1, value: switchValue(config.Notifications.Enabled)},
+	}
+	se.focus = 0
+	se.errText = ""
+}
+
+func wholeMinutes(d time.Duration) int {
+	return int(d.Round(time.Minute) / time.Minute)
+}
+
+func switchValue(on bool) int {
+	if on {
+		return 1
+	}
+	return 0
+}
+
+// Update handles the keyboard and mouse for one frame.
+func (se *SettingsEditor) Update(screenWidth, screenHeight int) {
+	se.layout(screenWidth, screenHeight)
+	count := len(se.settings) + len(se.buttons)
+	backward := ebiten.IsKeyPressed(ebiten.KeyShift)
+	
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyEscape):
+		se.close()
+		return
+	case inpututil.IsKeyJustPressed(ebiten.KeyDown), inpututil.IsKeyJustPressed(ebiten.KeyTab) && !backward:
+		se.focus = (se.focus + 1) % count
+	case inpututil.IsKeyJustPressed(ebiten.KeyUp), inpututil.IsKeyJustPressed(ebiten.KeyTab) && backward:
+		se.focus = (se.focus + count - 1) % count
+	case inpututil.IsKeyJustPressed(ebiten.KeyLeft):
+		se.change(se.focus, -1)
+	case inpututil.IsKeyJustPressed(ebiten.KeyRight):
+		se.change(se.focus, 1)
+	case inpututil.IsKeyJustPressed(ebiten.KeyBackspace):
+		if s := se.focused(); s != nil && s.input != "" {
+			s.input = s.input[:len(s.input)-1]
+		}
+	case inpututil.IsKeyJustPressed(ebiten.KeySpace):
+		se.activate()
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnter), inpututil.IsKeyJustPressed(ebiten.KeyKPEnter):
+		// 設定行では ENTER で保存し、ボタンではそのボタンを押す
+		if se.focused() != nil {
+			se.submit()
+		} else {
+			se.activate()
+		}
+	}
+	
+	if s := se.focused(); s != nil && s.kind != settingSwitch {
+		for _, r := range ebiten.AppendInputChars(nil) {
+			if r >= '0' && r <= '9' && len(s.input) < 3 {
+				s.input += string(r)
+			}
+		}
+	}
+	
+	se.updateMouse()
+}
+
+// focused returns the setting with the focus, or nil when a button has it.
+func (se *SettingsEditor) focused() *setting {
+	if se.focus < len(se.settings) {
+		return &se.settings[se.focus]
+	}
+	return nil
+}
+
+// activate toggles a focused switch or presses a focused button.
+func (se *SettingsEditor) activate() {
+	if s := se.focused(); s != nil {
+		if s.kind == settingSwitch {
+			se.change(se.focus, 1)
+		}
+		return
+	}
+	button := &se.buttons[se.focus-len(se.settings)]
+	if button.IsAvailable() {
+		button.Action()
+	}
+}
+
+// change steps a number by its step in direction, or flips a switch.
+func (se *SettingsEditor) change(row, direction int) {
+	if row >= len(se.settings) {
+		return
+	}
+	s := &se.settings[row]
+	if s.kind == settingSwitch {
+		s.value = 1 - s.value
+		return
+	}
+	if value, err := strconv.Atoi(s.input); err == nil {
+		s.value = value
+	}
+	s.input = ""
+	s.value = max(s.min, min(s.max, s.value+direction*s.step))
+}
+
+func (se *SettingsEditor) updateMouse() {
+	mx, my := ebiten.CursorPosition()
+	clicked := inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft)
+	hit := func(button *Button) bool {
+		button.Hovered = mx >= button.X && mx < button.X+button.W && my >= button.Y && my < button.Y+button.H
+		return button.Hovered && clicked && button.IsAvailable()
+	}
+	
+	for i := range se.controls {
+		control := &se.controls[i]
+		if hit(&control.Button) {
+			se.focus = control.row
+			control.Action()
+		}
+	}
+	for i := range se.buttons {
+		if hit(&se.buttons[i]) {
+			se.focus = len(se.settings) + i
+			se.buttons[i].Action()
+		}
+	}
+}
+
+// layout places the rows and buttons for the window size.
+func (se *SettingsEditor) layout(screenWidth, screenHeight int) {
+	controlX := screenWidth/2 + SettingsWidth/2 - 2*SettingsStepperSize - SettingsValueWidth
+	se.controls = se.controls[:0]
+	for row, s := range se.settings {
+		y := se.rowY(row) + (SettingsRowHeight-SettingsStepperSize)/2
+		if s.kind == settingSwitch {
+			se.controls = append(se.controls, settingControl{row: row, Button: Button{
+				X: controlX, Y: y, W: 2*SettingsStepperSize + SettingsValueWidth, H: SettingsStepperSize,
+				Text:   se.valueText(s),
+				Action: func() { se.change(row, 1) },
+			}})
+			continue
+		}
+		se.controls = append(se.controls,
+			settingControl{row: row, Button: Button{
+				X: controlX, Y: y, W: SettingsStepperSize, H: SettingsStepperSize,
+				Text:   "-",
+				Action: func() { se.change(row, -1) },
+			}},
+			settingControl{row: row, Button: Button{
+				X: controlX + SettingsStepperSize + SettingsValueWidth, Y: y, W: SettingsStepperSize, H: SettingsStepperSize,
+				Text:   "+",
+				Action: func() { se.change(row, 1) },
+			}},
+		)
+	}
+	
+	// 試聴ボタンを1行、保存とキャンセルをその下に並べる
+	previewY := se.rowY(len(se.settings)) + ButtonPadding
+	previews := len(se.buttons) - 2
+	previewWidth := (SettingsWidth - (previews-1)*ButtonPadding) / previews
+	for i := 0; i < previews; i++ {
+		se.buttons[i].X = screenWidth/2 - SettingsWidth/2 + i*(previewWidth+ButtonPadding)
+		se.buttons[i].Y = previewY
+		se.buttons[i].W = previewWidth
+		se.buttons[i].H = SettingsButtonHeight
+	}
+	
+	actionY := previewY + SettingsButtonHeight + ButtonPadding
+	for i, button := range se.buttons[previews:] {
+		button.X = screenWidth/2 - SettingsButtonWidth - ButtonPadding/2 + i*(SettingsButtonWidth+ButtonPadding)
+		button.Y = actionY
+		button.W = SettingsButtonWidth
+		button.H = SettingsButtonHeight
+		se.buttons[previews+i] = button
+	}
+}
+
+func (se *SettingsEditor) rowY(row int) int {
+	return SettingsTop + row*SettingsRowHeight
+}
+
+// valueText shows a setting's value, or the digits being typed into it.
+func (se *SettingsEditor) valueText(s setting) string {
+	switch {
+	case s.input != "":
+		return s.input + "_"
+	case s.kind == settingSwitch && s.value == 1:
+		return se.text("settings.on", i18n.Data{})
+	case s.kind == settingSwitch:
+		return se.text("settings.off", i18n.Data{})
+	case s.kind == settingPercent:
+		return se.text("settings.percent", i18n.Data{Count: s.value})
+	default:
+		return se.text("settings.minutes", i18n.Data{Count: s.value})
+	}
+}
+
+// commit takes the typed digits into the values, reporting the first
+// setting whose input is out of range.
+func (se *SettingsEditor) commit() error {
+	for row := range se.settings {
+		s := &se.settings[row]
+		if s.input == "" {
+			continue
+		}
+		value, err := strconv.Atoi(s.input)
+		if err != nil || value < s.min || value > s.max {
+			se.focus = row
+			return fmt.Errorf("%s: %s", se.text(s.label, i18n.Data{}), se.text("settings.range", i18n.Data{Count: s.min, Limit: s.max}))
+		}
+		s.value = value
+		s.input = ""
+	}
+	return nil
+}
+
+// submit validates the settings and saves them, staying on the screen with
+// the error if they cannot be applied.
+func (se *SettingsEditor) submit() {
+	if err := se.commit(); err != nil {
+		se.errText = err.Error()
+		return
+	}
+	
+	config := se.configService.GetConfig().Clone()
+	config.WorkDuration = time.Duration(se.settings[settingWork].value) * time.Minute
+	config.BreakDuration = time.Duration(se.settings[settingBreak].value) * time.Minute
+	config.WarningInterval = time.Duration(se.settings[settingWarning].value) * time.Minute
+	config.Volume = float64(se.settings[settingVolume].value) / 100
+	config.SoundEnabled = se.settings[settingSound].value == 1
+	config.Notifications.Enabled = se.settings[settingNotifications].value == 1
+	
+	if err := se.save(config); err != nil {
+		se.errText = err.Error()
+		return
+	}
+	se.close()
+}
+
+func (se *SettingsEditor) close() {
+	se.errText = ""
+	if se.onClose != nil {
+		se.onClose()
+	}
+}
+
+// preview plays a sound at the volume being edited, even with sound off.
+func (se *SettingsEditor) preview(play func(domain.AudioPlayer) error) {
+	audioService, ok := se.audio.(*application.AudioService)
+	if !ok {
+		return
+	}
+	volume := se.settings[settingVolume]
+	if value, err := strconv.Atoi(volume.input); err == nil && value >= volume.min && value <= volume.max {
+		volume.value = value
+	}
+	player := audioService.Preview(float64(volume.value) / 100)
+	go func() {
+		if err := play(player); err != nil {
+			log.Printf("Sound preview failed: %v", err)
+		}
+	}()
+}
+
+func (se *SettingsEditor) Draw(screen *ebiten.Image) {
+	screenWidth, screenHeight := ebiten.WindowSize()
+	left := screenWidth/2 - SettingsWidth/2
+	
+	drawCenteredText(screen, se.text("settings.title", i18n.Data{}), se.fonts.Large, screenWidth/2, SettingsTitleY, se.theme.Text)
+	
+	for row, s := range se.settings {
+		y := se.rowY(row)
+		textY := y + (SettingsRowHeight-lineHeight(se.fonts.Body))/2
+		drawTextAt(screen, se.text(s.label, i18n.Data{}), se.fonts.Body, left, textY, se.theme.Text)
+		if s.kind != settingSwitch {
+			valueX := left + SettingsWidth - SettingsStepperSize - SettingsValueWidth/2
+			drawCenteredText(screen, se.valueText(s), se.fonts.Body, valueX, textY, se.theme.Text)
+		}
+		if row == se.focus {
+			drawBorder(screen, left-ButtonPadding, y, SettingsWidth+2*ButtonPadding, SettingsRowHeight, se.theme.Text, FocusRingWidth)
+		}
+	}
+	
+	for i := range se.controls {
+		drawButton(screen, &se.controls[i].Button, se.fonts, se.theme)
+	}
+	for i := range se.buttons {
+		button := &se.buttons[i]
+		drawButton(screen, button, se.fonts, se.theme)
+		if len(se.settings)+i == se.focus {
+			drawFocusRing(screen, button, se.theme.Text)
+		}
+	}
+	
+	bottom := se.buttons[len(se.buttons)-1].Y + SettingsButtonHeight + ButtonPadding
+	if se.errText != "" {
+		drawCenteredText(screen, se.errText, se.fonts.Body, screenWidth/2, bottom, se.theme.Text)
+	} else {
+		drawCenteredText(screen, se.text("settings.next_session", i18n.Data{}), se.fonts.Body, screenWidth/2, bottom, se.theme.MutedText)
+	}
+	drawCenteredText(screen, se.text("settings.keys", i18n.Data{}), se.fonts.Button, screenWidth/2, screenHeight-2*ButtonPadding-lineHeight(se.fonts.Button), se.theme.MutedText)
+}
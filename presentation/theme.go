@@ -0,0 +1,50 @@
+package presentation
+
+import (
+	"log"
+	"time"
+
+	"karedoro/application"
+	"karedoro/theme"
+)
+
+// themeSelector follows the theme chosen in the config, including the
+// switch to the dark theme by time of day, and loads it when it changes.
+type themeSelector struct {
+	configService *application.ConfigService
+	name          string
+	current       *theme.Theme
+}
+
+func newThemeSelector(configService *application.ConfigService) *themeSelector {
+	ts := &themeSelector{configService: configService}
+	ts.update(time.Now())
+	return ts
+}
+
+// update loads the theme to use at now and reports whether it changed. A
+// theme that cannot be loaded falls back to the default theme.
+func (ts *themeSelector) update(now time.Time) bool {
+	name := ts.configService.GetConfig().Theme.Active(now)
+	if ts.current != nil && name == ts.name {
+		return false
+	}
+	
+	ts.name = name
+	t, err := theme.Load(name, ts.configService.Dir())
+	if err != nil {
+		log.Printf("Warning: using the default theme: %v", err)
+		t = theme.Default()
+	}
+	ts.current = t
+	return true
+}
+
+// cycle saves the next built-in theme in the config, turning off the
+// automatic dark theme so that the choice sticks.
+func (ts *themeSelector) cycle() error {
+	config := ts.configService.GetConfig().Clone()
+	config.Theme.Name = theme.Next(ts.current.Name)
+	config.Theme.AutoDark = false
+	return ts.configService.UpdateConfig(config)
+}
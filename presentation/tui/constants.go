@@ -0,0 +1,32 @@
+package tui
+
+// ANSI escape sequences.
+const (
+	escHome        = "\x1b[H"
+	escReset       = "\x1b[0m"
+	escBold        = "\x1b[1m"
+	escHideCursor  = "\x1b[?25l"
+	escShowCursor  = "\x1b[?25h"
+	escAltScreen   = "\x1b[?1049h"
+	escMainScreen  = "\x1b[?1049l"
+	escBell        = "\a"
+)
+
+// Background and foreground colours for each screen state, matching the
+// window's palette as closely as the basic ANSI colours allow.
+const (
+	idleColor      = "\x1b[48;5;235m\x1b[97m"
+	workColor      = "\x1b[48;5;160m\x1b[97m"
+	breakColor     = "\x1b[48;5;28m\x1b[97m"
+	flowColor      = "\x1b[48;5;25m\x1b[97m"
+	overtimeColor  = "\x1b[48;5;90m\x1b[97m"
+	overlayColor   = "\x1b[48;5;196m\x1b[97m"
+	flashColor     = "\x1b[48;5;16m\x1b[97m"
+)
+
+// Layout.
+const (
+	progressBarWidth = 40
+	defaultWidth     = 80
+	defaultHeight    = 24
+)
@@ -0,0 +1,37 @@
+package tui
+
+// key is a single key press read from the terminal.
+type key rune
+
+const (
+	keyNone   key = 0
+	keyCtrlC  key = 0x03
+	keyEnter  key = '\r'
+	keyEscape key = 0x1b
+	keySpace  key = ' '
+)
+
+// parseKeys splits a chunk read from the terminal into key presses. Escape
+// sequences such as arrow keys are skipped; a lone ESC is reported as
+// keyEscape.
+func parseKeys(buf []byte) []key {
+	var keys []key
+	for i := 0; i < len(buf); i++ {
+		b := buf[i]
+		switch {
+		case b == 0x1b && i+1 < len(buf) && (buf[i+1] == '[' || buf[i+1] == 'O'):
+			// CSI/SS3 シーケンスは最終バイトまで読み飛ばす
+			i += 2
+			for i < len(buf) && (buf[i] < 0x40 || buf[i] > 0x7e) {
+				i++
+			}
+		case b == '\n':
+			keys = append(keys, keyEnter)
+		case b >= 'A' && b <= 'Z':
+			keys = append(keys, key(b-'A'+'a'))
+		default:
+			keys = append(keys, key(b))
+		}
+	}
+	return keys
+}
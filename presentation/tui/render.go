@@ -0,0 +1,65 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/text/width"
+)
+
+// center pads text to width, re-applying background after any reset that
+// text contains.
+func center(text string, width int, background string) string {
+	visible := visibleLen(text)
+	if visible >= width {
+		return text
+	}
+	left := (width - visible) / 2
+	text = strings.ReplaceAll(text, escReset, escReset+background)
+	return strings.Repeat(" ", left) + text + strings.Repeat(" ", width-visible-left)
+}
+
+// visibleLen counts the terminal cells taken by the text outside escape
+// sequences; wide characters such as kanji take two.
+func visibleLen(text string) int {
+	n := 0
+	for i := 0; i < len(text); {
+		if text[i] == 0x1b {
+			// エスケープシーケンスは最終バイトまで数えない
+			i++
+			for i < len(text) && (text[i] < 0x40 || text[i] > 0x7e || text[i] == '[') {
+				i++
+			}
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(text[i:])
+		i += size
+		n++
+		if kind := width.LookupRune(r).Kind(); kind == width.EastAsianWide || kind == width.EastAsianFullwidth {
+			n++
+		}
+	}
+	return n
+}
+
+func bold(text string) string {
+	return escBold + text + escReset
+}
+
+func clock(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(d.Minutes()), int(d.Seconds())%60)
+}
+
+func progressBar(progress float64) string {
+	if progress < 0 {
+		progress = 0
+	}
+	if progress > 1 {
+		progress = 1
+	}
+	filled := int(progress * progressBarWidth)
+	return "[" + strings.Repeat("█", filled) + strings.Repeat("░", progressBarWidth-filled) + "]"
+}
@@ -0,0 +1,427 @@
+// Package tui is a terminal front-end for karedoro, for sessions where no
+// window can be opened (e.g. tmux over SSH). It drives the same application
+// services as the ebiten window and mirrors its screens with ANSI escapes.
+package tui
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"karedoro/application"
+	"karedoro/domain"
+	"karedoro/i18n"
+)
+
+// App is the terminal UI. It is driven by Run; the other methods are only
+// called from Run's goroutine.
+type App struct {
+	services *application.Services
+	messages *i18n.Catalog
+	out      io.Writer
+	
+	// overlay is the terminal-wide end-of-session prompt, the counterpart of
+	// the window's FullscreenOverlay.
+	overlay  bool
+	flashing bool
+	quit     bool
+	bell     bool
+}
+
+func New(services *application.Services, out io.Writer) *App {
+	app := &App{
+		services: services,
+		messages: services.Messages,
+		out:      out,
+	}
+	if app.messages == nil {
+		app.messages = i18n.Default()
+	}
+	app.setupEventCallbacks()
+	return app
+}
+
+// Run takes over the terminal until the user quits.
+func Run(services *application.Services) error {
+	restore, err := makeRaw(os.Stdin)
+	if err != nil {
+		return fmt.Errorf("terminal not supported: %w", err)
+	}
+	defer restore()
+	
+	// ログは画面を崩すので、端末を使っている間はファイルに書く
+	if services.Config != nil {
+		logFile, err := os.OpenFile(filepath.Join(services.Config.Dir(), "tui.log"), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err == nil {
+			log.SetOutput(logFile)
+			defer func() {
+				log.SetOutput(os.Stderr)
+				logFile.Close()
+			}()
+		}
+	}
+	
+	out := bufio.NewWriter(os.Stdout)
+	app := New(services, out)
+	if services.Audio != nil && services.Notification != nil {
+		services.NewFeedbackHandler().Attach(services.Session)
+	}
+	
+	fmt.Fprint(out, escAltScreen, escHideCursor)
+	defer func() {
+		fmt.Fprint(out, escReset, escShowCursor, escMainScreen)
+		out.Flush()
+	}()
+	
+	keys := make(chan []key)
+	go readKeys(os.Stdin, keys)
+	
+	ticker := time.NewTicker(application.LoopInterval)
+	defer ticker.Stop()
+	
+	for !app.quit {
+		select {
+		case pressed, ok := <-keys:
+			if !ok {
+				return nil
+			}
+			for _, k := range pressed {
+				app.HandleKey(k)
+			}
+		case <-ticker.C:
+			if services.Loop != nil {
+				services.Loop.RunPending()
+			}
+			services.Session.Update()
+		}
+		
+		width, height := terminalSize(os.Stdout)
+		app.Draw(width, height)
+		out.Flush()
+	}
+	return nil
+}
+
+func readKeys(in io.Reader, keys chan<- []key) {
+	defer close(keys)
+	buf := make([]byte, 64)
+	for {
+		n, err := in.Read(buf)
+		if n > 0 {
+			keys <- parseKeys(buf[:n])
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (a *App) setupEventCallbacks() {
+	sessionService := a.services.Session
+	
+	showOverlay := func() {
+		a.overlay = true
+		a.bell = true
+	}
+	hideOverlay := func() {
+		a.overlay = false
+		a.flashing = false
+	}
+	
+	sessionService.AddEventCallback(domain.EventWorkSessionEnd, showOverlay)
+	sessionService.AddEventCallback(domain.EventBreakSessionEnd, showOverlay)
+	sessionService.AddEventCallback(domain.EventFlowSessionEnd, showOverlay)
+	
+	// Escalated warnings re-assert the overlay; the last step also flashes it
+	sessionService.AddEventCallback(domain.EventWarningUrgent, showOverlay)
+	sessionService.AddEventCallback(domain.EventWarningCritical, func() {
+		showOverlay()
+		a.flashing = true
+	})
+	
+	// Overtime runs on the main screen; when it ends without a break the overlay returns
+	sessionService.AddEventCallback(domain.EventOvertimeStart, hideOverlay)
+	sessionService.AddEventCallback(domain.EventOvertimeEnd, func() {
+		if sessionService.GetSession().GetState() == domain.Idle {
+			showOverlay()
+		}
+	})
+	
+	// An abandoned session returns to the main screen rather than the overlay
+	sessionService.AddEventCallback(domain.EventSessionAbandon, hideOverlay)
+	
+	sessionService.AddEventCallback(domain.EventWorkSessionStart, hideOverlay)
+	sessionService.AddEventCallback(domain.EventBreakSessionStart, hideOverlay)
+	sessionService.AddEventCallback(domain.EventFlowSessionStart, hideOverlay)
+}
+
+// HandleKey applies a key press. The keys match the window's InputHandler,
+// with letters standing in for the buttons that the window offers.
+func (a *App) HandleKey(k key) {
+	sessionService := a.services.Session
+	session := sessionService.GetSession()
+	
+	if k == keyCtrlC || (k == 'q' && !a.overlay) {
+		a.quit = true
+		return
+	}
+	
+	switch session.GetState() {
+	case domain.WorkSession, domain.BreakSession, domain.FlowSession:
+		switch {
+		case k == keyEnter && session.GetState() == domain.FlowSession:
+			sessionService.StopFlowSession()
+		case k == keySpace && session.IsSessionPaused():
+			sessionService.ResumeSession()
+		case k == keySpace && session.CanPause():
+			sessionService.PauseSession()
+		case k == '+' && session.CanExtend():
+			sessionService.ExtendSession(session.GetExtendPolicy().Step)
+		}
+	case domain.Overtime:
+		switch k {
+		case 'b':
+			sessionService.StartBreakSession()
+		case keyEscape:
+			sessionService.StopOvertime()
+		}
+	case domain.Idle:
+		switch {
+		case k == keyEscape:
+			sessionService.CancelAutoStart()
+		case session.IsBreakDue():
+			switch {
+			case k == 'b':
+				sessionService.StartBreakSession()
+			case k == 'k' && session.CanContinueOvertime():
+				sessionService.ContinueOvertime()
+			case k == 's' && session.CanSkipBreak():
+				sessionService.SkipBreak()
+			}
+		case k == 'w':
+			sessionService.StartWorkSession()
+		case k == 'f':
+			sessionService.StartFlowSession()
+		}
+	}
+}
+
+// Draw writes one full frame for a terminal of the given size.
+func (a *App) Draw(width, height int) {
+	if width <= 0 || height <= 0 {
+		width, height = defaultWidth, defaultHeight
+	}
+	
+	background, lines := a.view()
+	
+	var b strings.Builder
+	b.WriteString(escHome)
+	if a.bell {
+		b.WriteString(escBell)
+		a.bell = false
+	}
+	
+	top := (height - len(lines)) / 2
+	if top < 0 {
+		top = 0
+	}
+	for row := 0; row < height; row++ {
+		b.WriteString(background)
+		text := ""
+		if i := row - top; i >= 0 && i < len(lines) {
+			text = lines[i]
+		}
+		b.WriteString(center(text, width, background))
+		if row < height-1 {
+			b.WriteString("\r\n")
+		}
+	}
+	b.WriteString(escReset)
+	io.WriteString(a.out, b.String())
+}
+
+// view returns the background colour and the lines of the current screen.
+func (a *App) view() (string, []string) {
+	session := a.services.Session.GetSession()
+	
+	if a.overlay && session.GetState() == domain.Idle {
+		background := overlayColor
+		if a.flashing && time.Now().UnixMilli()/500%2 == 0 {
+			background = flashColor
+		}
+		return background, a.overlayLines(session)
+	}
+	
+	switch session.GetState() {
+	case domain.WorkSession:
+		return workColor, a.timedLines(session, a.text("screen.working"))
+	case domain.BreakSession:
+		return breakColor, a.timedLines(session, a.text("screen.break"))
+	case domain.FlowSession:
+		return flowColor, a.flowLines(session)
+	case domain.Overtime:
+		return overtimeColor, a.overtimeLines(session)
+	default:
+		return idleColor, a.idleLines(session)
+	}
+}
+
+func (a *App) timedLines(session *domain.Session, statusText string) []string {
+	lines := []string{
+		bold(clock(session.GetTimeRemaining())),
+		"",
+		progressBar(session.GetProgress()),
+		"",
+	}
+	
+	if session.IsSessionPaused() {
+		lines = append(lines, a.text("screen.paused"), a.text("tui.resume"))
+	} else {
+		lines = append(lines, statusText, a.pauseInstruction(session))
+	}
+	
+	extend := ""
+	if session.Extensions() > 0 {
+		extend = a.textFor("screen.extended", session.ExtendedBy())
+	}
+	if session.CanExtend() {
+		if extend != "" {
+			extend += "   "
+		}
+		extend += a.textFor("tui.extend", session.GetExtendPolicy().Step)
+	}
+	if extend != "" {
+		lines = append(lines, extend)
+	}
+	return append(lines, "", a.text("tui.quit"))
+}
+
+func (a *App) flowLines(session *domain.Session) []string {
+	lines := []string{
+		bold(clock(session.FlowElapsed())),
+		"",
+		a.textFor("screen.flow_break", session.EarnedFlowBreak()),
+		"",
+	}
+	if session.IsSessionPaused() {
+		lines = append(lines, a.text("screen.paused"), a.text("tui.resume"))
+	} else {
+		lines = append(lines, a.text("screen.flow"), a.pauseInstruction(session))
+	}
+	return append(lines, a.text("tui.flow_stop"), "", a.text("tui.quit"))
+}
+
+func (a *App) overtimeLines(session *domain.Session) []string {
+	remaining := session.OvertimeRemaining()
+	return []string{
+		bold("+" + clock(session.OvertimeElapsed())),
+		"",
+		progressBar(session.GetOvertimeProgress()),
+		"",
+		a.text("screen.overtime"),
+		a.textFor("screen.overtime_cap", remaining),
+		a.text("tui.overtime_keys"),
+	}
+}
+
+func (a *App) idleLines(session *domain.Session) []string {
+	lines := []string{a.text("screen.idle"), ""}
+	if session.IsBreakDue() {
+		lines = append(lines, a.key("B", "button.start_break"))
+	} else {
+		lines = append(lines, a.key("W", "button.start_work"), a.key("F", "button.start_flow"))
+	}
+	
+	if a.services.Stats != nil {
+		today := a.services.Stats.Today()
+		lines = append(lines, "", a.messages.Text("screen.today_stats", i18n.Data{
+			CompletedToday: today.WorkSessionsCompleted,
+			SkippedToday:   today.BreaksSkipped,
+		}))
+	}
+	return append(lines, "", a.text("tui.quit"))
+}
+
+// overlayLines is the end-of-session prompt, with the same choices as the
+// window's overlay buttons.
+func (a *App) overlayLines(session *domain.Session) []string {
+	var lines []string
+	switch session.GetSessionType() {
+	case domain.Work:
+		lines = append(lines, bold(a.text("overlay.work_end")))
+	case domain.Flow:
+		lines = append(lines, bold(a.text("overlay.flow_end")))
+	default:
+		lines = append(lines, bold(a.text("overlay.break_end")))
+	}
+	lines = append(lines, a.text("overlay.cannot_continue"), "")
+	
+	if session.IsBreakDue() {
+		lines = append(lines, a.key("B", "button.start_break"))
+		if session.CanContinueOvertime() {
+			lines = append(lines, a.key("K", "button.keep_going"))
+		}
+		if session.CanSkipBreak() {
+			lines = append(lines, a.key("S", "button.skip_break"))
+		}
+		lines = append(lines, "", a.skipAllowanceText(session))
+		if next := session.NextBreakDuration(); next != session.GetDuration(domain.Break) || session.GetSessionType() == domain.Flow {
+			lines = append(lines, a.textFor("overlay.next_break", next))
+		}
+	} else {
+		lines = append(lines, a.key("W", "button.start_work"), a.key("F", "button.start_flow"))
+	}
+	
+	if session.IsAutoStartPending() {
+		lines = append(lines, "", a.messages.Text("overlay.auto_start", i18n.Data{
+			Session: a.messages.SessionType(session.NextSessionType()),
+			Count:   int(session.AutoStartRemaining().Seconds()) + 1,
+		}))
+	}
+	return lines
+}
+
+func (a *App) pauseInstruction(session *domain.Session) string {
+	switch {
+	case session.GetPausePolicy().Strict:
+		return a.text("screen.strict")
+	case !session.CanPause():
+		return a.text("screen.no_pauses")
+	default:
+		return a.text("tui.pause")
+	}
+}
+
+func (a *App) skipAllowanceText(session *domain.Session) string {
+	remaining := session.SkipsRemaining()
+	switch {
+	case remaining > 0:
+		return a.messages.Text("skip.left", i18n.Data{Count: remaining})
+	case remaining < 0:
+		return ""
+	}
+	
+	if cooldown := session.SkipCooldownRemaining(); cooldown > 0 {
+		return a.textFor("skip.cooldown", cooldown)
+	}
+	return a.text("skip.none")
+}
+
+func (a *App) text(key string) string {
+	return a.messages.Text(key, i18n.Data{})
+}
+
+// textFor renders a message about a length of time.
+func (a *App) textFor(key string, d time.Duration) string {
+	return a.messages.Text(key, i18n.Data{Duration: i18n.Duration(d)})
+}
+
+// key labels a key with the window's button text, e.g. "W: START WORK SESSION".
+func (a *App) key(key, button string) string {
+	return key + ": " + a.text(button)
+}
@@ -0,0 +1,160 @@
+package tui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"karedoro/application"
+	"karedoro/domain"
+	"karedoro/i18n"
+)
+
+func newTestApp(t *testing.T) (*App, *bytes.Buffer) {
+	t.Helper()
+	services := &application.Services{Session: application.NewSessionService()}
+	out := &bytes.Buffer{}
+	return New(services, out), out
+}
+
+func containsLine(lines []string, text string) bool {
+	for _, line := range lines {
+		if strings.Contains(line, text) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestParseKeys(t *testing.T) {
+	keys := parseKeys([]byte("W\x1b[A \n\x1b+\x03"))
+	expected := []key{'w', keySpace, keyEnter, keyEscape, '+', keyCtrlC}
+	
+	if len(keys) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, keys)
+	}
+	for i := range expected {
+		if keys[i] != expected[i] {
+			t.Errorf("Key %d: expected %q, got %q", i, expected[i], keys[i])
+		}
+	}
+}
+
+func TestApp_HandleKey(t *testing.T) {
+	app, _ := newTestApp(t)
+	session := app.services.Session.GetSession()
+	
+	app.HandleKey('w')
+	if session.GetState() != domain.WorkSession {
+		t.Fatalf("W should start a work session, got %v", session.GetState())
+	}
+	
+	app.HandleKey(keySpace)
+	if !session.IsSessionPaused() {
+		t.Error("Space should pause the session")
+	}
+	app.HandleKey(keySpace)
+	if session.IsSessionPaused() {
+		t.Error("Space should resume the session")
+	}
+	
+	app.HandleKey('+')
+	if session.Extensions() != 1 {
+		t.Errorf("+ should extend the session, got %d extensions", session.Extensions())
+	}
+	
+	app.HandleKey('q')
+	if !app.quit {
+		t.Error("Q should quit")
+	}
+}
+
+func TestApp_OverlayOnSessionEnd(t *testing.T) {
+	app, out := newTestApp(t)
+	sessionService := app.services.Session
+	session := sessionService.GetSession()
+	session.SetDurations(20*time.Millisecond, 20*time.Millisecond)
+	
+	sessionService.StartWorkSession()
+	time.Sleep(30 * time.Millisecond)
+	sessionService.Update()
+	
+	if !app.overlay {
+		t.Fatal("Work session end should show the overlay")
+	}
+	background, lines := app.view()
+	if background != overlayColor && background != flashColor {
+		t.Error("Overlay should fill the terminal with the overlay colour")
+	}
+	if !containsLine(lines, "POMODORO COMPLETE! You MUST take a break!") || !containsLine(lines, "B: START BREAK SESSION") {
+		t.Errorf("Overlay should prompt for a break, got %q", lines)
+	}
+	
+	// Q must not dismiss the end-of-session prompt
+	app.HandleKey('q')
+	if app.quit {
+		t.Error("Q should not quit while the overlay is shown")
+	}
+	
+	app.Draw(40, 10)
+	if !strings.Contains(out.String(), escBell) {
+		t.Error("Overlay should ring the terminal bell")
+	}
+	
+	app.HandleKey('b')
+	if session.GetState() != domain.BreakSession {
+		t.Errorf("B should start the break, got %v", session.GetState())
+	}
+	if app.overlay {
+		t.Error("Starting the break should hide the overlay")
+	}
+}
+
+func TestApp_Draw(t *testing.T) {
+	app, out := newTestApp(t)
+	app.HandleKey('w')
+	
+	app.Draw(60, 12)
+	frame := out.String()
+	
+	if !strings.HasPrefix(frame, escHome) {
+		t.Error("Frame should start at the top-left corner")
+	}
+	if strings.Count(frame, "\r\n") != 11 {
+		t.Errorf("Frame should fill 12 rows, got %d", strings.Count(frame, "\r\n")+1)
+	}
+	if !strings.Contains(frame, workColor) || !strings.Contains(frame, "WORKING - STAY FOCUSED!") {
+		t.Error("Work session should be drawn in the work colour")
+	}
+}
+
+func TestCenter(t *testing.T) {
+	line := center(bold("ab"), 6, idleColor)
+	if visibleLen(line) != 6 {
+		t.Errorf("Expected 6 visible cells, got %d", visibleLen(line))
+	}
+	if !strings.HasPrefix(line, "  "+escBold) {
+		t.Errorf("Text should be centred, got %q", line)
+	}
+}
+
+func TestVisibleLen_WideCharacters(t *testing.T) {
+	if got := visibleLen(bold("作業中 - OK")); got != 11 {
+		t.Errorf("Expected kanji to take two cells each, got %d", got)
+	}
+}
+
+func TestApp_Japanese(t *testing.T) {
+	messages, err := i18n.Load("ja", "")
+	if err != nil {
+		t.Fatalf("Load should not return error, got %v", err)
+	}
+	services := &application.Services{Session: application.NewSessionService(), Messages: messages}
+	app := New(services, &bytes.Buffer{})
+	
+	_, lines := app.view()
+	if !containsLine(lines, "W: 作業を開始") {
+		t.Errorf("Idle screen should use the Japanese catalog, got %q", lines)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"github.com/hajimehoshi/ebiten/v2"
 	"karedoro/application"
 	"karedoro/domain"
+	"karedoro/i18n"
+	"karedoro/theme"
 )
 
 // UIManager manages the overall UI state and coordinates screen rendering.
@@ -12,14 +14,18 @@ type UIManager struct {
 	isFullscreen    bool
 	buttonManager   *ButtonManager
 	screenRenderer  *ScreenRenderer
+	settingsEditor  *SettingsEditor
+	theme           *theme.Theme
 }
 
-func NewUIManager() *UIManager {
+func NewUIManager(settingsEditor *SettingsEditor) *UIManager {
 	return &UIManager{
 		currentScreen:  MainScreen,
 		isFullscreen:   false,
 		buttonManager:  NewButtonManager(),
 		screenRenderer: NewScreenRenderer(),
+		settingsEditor: settingsEditor,
+		theme:          theme.Default(),
 	}
 }
 
@@ -39,6 +45,56 @@ func (ui *UIManager) IsFullscreen() bool {
 	return ui.isFullscreen
 }
 
+// SetFlashing toggles the flashing fullscreen overlay used by the last warning step.
+func (ui *UIManager) SetFlashing(flashing bool) {
+	ui.screenRenderer.SetFlashing(flashing)
+}
+
+// SetMessages renders the screen texts and button labels from messages.
+func (ui *UIManager) SetMessages(messages *i18n.Catalog) {
+	ui.buttonManager.SetMessages(messages)
+	ui.screenRenderer.SetMessages(messages)
+	ui.settingsEditor.SetMessages(messages)
+}
+
+// SetFonts draws the screen texts and button labels with fonts.
+func (ui *UIManager) SetFonts(fonts *Fonts) {
+	ui.buttonManager.SetFonts(fonts)
+	ui.screenRenderer.SetFonts(fonts)
+	ui.settingsEditor.SetFonts(fonts)
+}
+
+// SetTheme draws the window in the colours of t.
+func (ui *UIManager) SetTheme(t *theme.Theme) {
+	ui.theme = t
+	ui.buttonManager.SetTheme(t)
+	ui.screenRenderer.SetTheme(t)
+	ui.settingsEditor.SetTheme(t)
+}
+
+// SetKeymap presses the buttons with the keys of keymap and names them in the hints.
+func (ui *UIManager) SetKeymap(keymap *Keymap) {
+	ui.buttonManager.SetKeymap(keymap)
+	ui.screenRenderer.SetKeymap(keymap)
+}
+
+// ToggleStats shows or hides today's counts on the idle screen.
+func (ui *UIManager) ToggleStats() {
+	ui.screenRenderer.ToggleStats()
+}
+
+// ButtonsShown reports whether the current screen shows its buttons: the
+// overlay, and the main screen between sessions.
+func (ui *UIManager) ButtonsShown(session *domain.Session) bool {
+	switch ui.currentScreen {
+	case FullscreenOverlay:
+		return true
+	case MainScreen:
+		return session.GetState() == domain.Idle
+	}
+	return false
+}
+
 func (ui *UIManager) GetButtonManager() *ButtonManager {
 	return ui.buttonManager
 }
@@ -55,17 +111,24 @@ func (ui *UIManager) UpdateButtons() {
 	ui.buttonManager.UpdateButtons()
 }
 
-func (ui *UIManager) Draw(screen *ebiten.Image, session *domain.Session) {
-	screen.Fill(BackgroundColor)
+func (ui *UIManager) Draw(screen *ebiten.Image, session *domain.Session, today application.DailyStats) {
+	screen.Fill(ui.theme.Background)
 	
 	switch ui.currentScreen {
 	case MainScreen:
-		ui.screenRenderer.DrawMainScreen(screen, session, ui.buttonManager)
+		ui.screenRenderer.DrawMainScreen(screen, session, today, ui.buttonManager)
 	case FullscreenOverlay:
 		ui.screenRenderer.DrawFullscreenOverlay(screen, session, ui.buttonManager)
+	case SettingsScreen:
+		ui.settingsEditor.Draw(screen)
 	}
 }
 
+// DrawMiniTimer draws the mini timer window in place of the current screen.
+func (ui *UIManager) DrawMiniTimer(screen *ebiten.Image, session *domain.Session) {
+	ui.screenRenderer.DrawMiniTimer(screen, session)
+}
+
 func (ui *UIManager) SetupMainButtons(screenWidth, screenHeight int, sessionService *application.SessionService) {
 	ui.buttonManager.SetupMainButtons(screenWidth, screenHeight, sessionService)
 }
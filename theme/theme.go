@@ -0,0 +1,198 @@
+// Package theme holds karedoro's colour themes: the built-in dark, light,
+// high-contrast and deuteranopia-safe palettes, and user themes from the
+// config directory.
+package theme
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultName is the theme used when none is chosen.
+const DefaultName = "dark"
+
+//go:embed themes/*.json
+var builtins embed.FS
+
+// Color is a colour written as "#rrggbb" or "#rrggbbaa" in theme files.
+type Color color.RGBA
+
+func (c Color) RGBA() (r, g, b, a uint32) {
+	return color.RGBA(c).RGBA()
+}
+
+func (c Color) String() string {
+	if c.A == 0xff {
+		return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+	}
+	return fmt.Sprintf("#%02x%02x%02x%02x", c.R, c.G, c.B, c.A)
+}
+
+func (c Color) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.String())
+}
+
+func (c *Color) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	parsed, err := ParseColor(s)
+	if err != nil {
+		return err
+	}
+	*c = parsed
+	return nil
+}
+
+// ParseColor reads "#rrggbb" or "#rrggbbaa".
+func ParseColor(s string) (Color, error) {
+	hex := strings.TrimPrefix(s, "#")
+	if !strings.HasPrefix(s, "#") || (len(hex) != 6 && len(hex) != 8) {
+		return Color{}, fmt.Errorf("invalid colour %q (want #rrggbb or #rrggbbaa)", s)
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return Color{}, fmt.Errorf("invalid colour %q (want #rrggbb or #rrggbbaa)", s)
+	}
+	return Color{R: uint8(v >> 24), G: uint8(v >> 16), B: uint8(v >> 8), A: uint8(v)}, nil
+}
+
+// Theme is the set of colours the window is drawn with.
+type Theme struct {
+	Name string `json:"-"`
+	
+	Background Color `json:"background"`
+	Text       Color `json:"text"`
+	MutedText  Color `json:"muted_text"`
+	
+	Button         Color `json:"button"`
+	ButtonHover    Color `json:"button_hover"`
+	ButtonPressed  Color `json:"button_pressed"`
+	ButtonDisabled Color `json:"button_disabled"`
+	ButtonText     Color `json:"button_text"`
+	ButtonBorder   Color `json:"button_border"`
+	ButtonShadow   Color `json:"button_shadow"`
+	
+	// Backgrounds of the running sessions.
+	Work     Color `json:"work"`
+	Break    Color `json:"break"`
+	Overtime Color `json:"overtime"`
+	Flow     Color `json:"flow"`
+	
+	// The fullscreen overlay, its flash and the box around its headline.
+	Overlay          Color `json:"overlay"`
+	OverlayFlash     Color `json:"overlay_flash"`
+	OverlayBox       Color `json:"overlay_box"`
+	OverlayBoxText   Color `json:"overlay_box_text"`
+	OverlayBoxBorder Color `json:"overlay_box_border"`
+	
+	// Shadow is cast by the countdown.
+	Shadow Color `json:"shadow"`
+	
+	ProgressBackground Color `json:"progress_background"`
+	Progress           Color `json:"progress"`
+	ProgressBorder     Color `json:"progress_border"`
+}
+
+// Names lists the built-in themes.
+func Names() []string {
+	entries, _ := builtins.ReadDir("themes")
+	var names []string
+	for _, entry := range entries {
+		names = append(names, strings.TrimSuffix(entry.Name(), ".json"))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Load returns the theme called name, or the default theme when name is
+// empty. A file dir/themes/<name>.json takes precedence over a built-in theme
+// of the same name; it starts from the built-in theme named in its "base"
+// field, the default theme if there is none, and overrides the colours it
+// sets.
+func Load(name, dir string) (*Theme, error) {
+	if name == "" {
+		name = DefaultName
+	}
+	
+	if dir != "" {
+		path := filepath.Join(dir, "themes", name+".json")
+		data, err := os.ReadFile(path)
+		if err == nil {
+			t, err := loadUser(name, data)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", path, err)
+			}
+			return t, nil
+		}
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	return builtin(name)
+}
+
+// Default returns the built-in default theme.
+func Default() *Theme {
+	t, err := builtin(DefaultName)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// Next returns the built-in theme after name, for cycling through them.
+func Next(name string) string {
+	names := Names()
+	for i, n := range names {
+		if n == name {
+			return names[(i+1)%len(names)]
+		}
+	}
+	return names[0]
+}
+
+func builtin(name string) (*Theme, error) {
+	data, err := builtins.ReadFile("themes/" + name + ".json")
+	if err != nil {
+		return nil, fmt.Errorf("unknown theme %q (built-in: %s)", name, strings.Join(Names(), ", "))
+	}
+	t := &Theme{Name: name}
+	if err := json.Unmarshal(data, t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func loadUser(name string, data []byte) (*Theme, error) {
+	var header struct {
+		Base string `json:"base"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, err
+	}
+	if header.Base == "" {
+		header.Base = DefaultName
+	}
+	
+	t, err := builtin(header.Base)
+	if err != nil {
+		return nil, err
+	}
+	t.Name = name
+	if err := json.Unmarshal(data, t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
@@ -0,0 +1,113 @@
+package theme
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestBuiltins_SetEveryColour(t *testing.T) {
+	for _, name := range Names() {
+		th, err := Load(name, "")
+		if err != nil {
+			t.Fatalf("Failed to load %s: %v", name, err)
+		}
+		v := reflect.ValueOf(*th)
+		for i := 0; i < v.NumField(); i++ {
+			c, ok := v.Field(i).Interface().(Color)
+			if ok && c.A == 0 {
+				t.Errorf("%s leaves %s unset", name, v.Type().Field(i).Name)
+			}
+		}
+	}
+}
+
+func TestNames(t *testing.T) {
+	want := []string{"dark", "deuteranopia", "high-contrast", "light"}
+	if got := Names(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestParseColor(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Color
+		ok   bool
+	}{
+		{"#dc143c", Color{R: 0xdc, G: 0x14, B: 0x3c, A: 0xff}, true},
+		{"#00000064", Color{A: 0x64}, true},
+		{"dc143c", Color{}, false},
+		{"#dc14", Color{}, false},
+		{"#zzzzzz", Color{}, false},
+	}
+	for _, tt := range tests {
+		got, err := ParseColor(tt.in)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("ParseColor(%q) = %v, %v", tt.in, got, err)
+		}
+	}
+	
+	if s := (Color{R: 0xdc, G: 0x14, B: 0x3c, A: 0xff}).String(); s != "#dc143c" {
+		t.Errorf("Expected #dc143c, got %s", s)
+	}
+}
+
+func TestLoad_UserTheme(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "themes"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(dir, "themes", "solarized.json")
+	if err := os.WriteFile(file, []byte(`{"base": "light", "work": "#dc322f"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	
+	th, err := Load("solarized", dir)
+	if err != nil {
+		t.Fatalf("Failed to load the user theme: %v", err)
+	}
+	light, _ := Load("light", "")
+	if th.Name != "solarized" || th.Work.String() != "#dc322f" {
+		t.Errorf("Expected the overridden work colour, got %s %s", th.Name, th.Work)
+	}
+	if th.Break != light.Break || th.Background != light.Background {
+		t.Error("Colours left out should come from the base theme")
+	}
+	
+	if err := os.WriteFile(file, []byte(`{"work": "red"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load("solarized", dir); err == nil {
+		t.Error("Expected an invalid colour to be rejected")
+	}
+	if err := os.WriteFile(file, []byte(`{"base": "neon"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load("solarized", dir); err == nil {
+		t.Error("Expected an unknown base to be rejected")
+	}
+}
+
+func TestLoad_Fallbacks(t *testing.T) {
+	th, err := Load("", t.TempDir())
+	if err != nil || th.Name != DefaultName {
+		t.Errorf("Expected the default theme, got %v, %v", th, err)
+	}
+	if _, err := Load("neon", t.TempDir()); err == nil {
+		t.Error("Expected an unknown theme to be rejected")
+	}
+}
+
+func TestNext(t *testing.T) {
+	if got := Next("dark"); got != "deuteranopia" {
+		t.Errorf("Expected deuteranopia after dark, got %s", got)
+	}
+	if got := Next("light"); got != "dark" {
+		t.Errorf("Expected to wrap around to dark, got %s", got)
+	}
+	if got := Next("solarized"); got != "dark" {
+		t.Errorf("Expected a user theme to continue with the first built-in, got %s", got)
+	}
+}